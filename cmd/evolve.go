@@ -0,0 +1,131 @@
+package main
+
+import (
+	"flag"
+	"flip7-simulator/internal/algorithms"
+	"flip7-simulator/internal/game"
+	"flip7-simulator/internal/simulator"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// runEvolve evolves weights for the Weighted algorithm against a fixed pool of
+// opponents, checkpointing every generation and writing out the best genome
+func runEvolve(args []string) error {
+	flags := flag.NewFlagSet("evolve", flag.ExitOnError)
+	population := flags.Int("population", simulator.DefaultPopulation, "Genomes in each generation")
+	generations := flags.Int("generations", simulator.DefaultGenerations, "Generations to evolve in total, counting those in a -resume checkpoint")
+	numGames := flags.Int("games", simulator.DefaultFitnessGames, "Games each genome plays to measure its fitness")
+	elite := flags.Int("elite", simulator.DefaultElite, "Fittest genomes carried into the next generation unchanged")
+	mutationRate := flags.Float64("mutation-rate", simulator.DefaultMutationRate, "Chance each weight of a child is mutated")
+	mutationScale := flags.Float64("mutation-scale", simulator.DefaultMutationScale, "Standard deviation of a mutation")
+	opponents := flags.String("opponents", "Stop at 25,Conservative,Adaptive", "Comma-separated opponent pool every genome plays against")
+	deckName := flags.String("deck", "official", "Deck composition to play with (official, legacy)")
+	targetScore := flags.Int("target", 200, "Score that ends the game")
+	seed := flags.Int64("seed", 1, "Seed for the run; the same seed evolves the same genomes")
+	workers := flags.Int("workers", 0, "Number of games to play in parallel (0 = one per CPU)")
+	checkpoints := flags.String("checkpoints", "evolve", "Directory to write a checkpoint to after every generation")
+	resume := flags.String("resume", "", "Carry on from this checkpoint file, keeping the settings it was started with")
+	outFile := flags.String("out", "genome.json", "File to write the best genome to")
+	name := flags.String("name", "Evolved", "Name the best genome plays under")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: flip7-simulator evolve [-generations 30] [-opponents \"Stop at 25,Adaptive\"] [-resume evolve/generation-0010.json]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	config := simulator.EvolveConfig{
+		Weights:       len(algorithms.GenomeFeatures),
+		Population:    *population,
+		Generations:   *generations,
+		Games:         *numGames,
+		Elite:         *elite,
+		MutationRate:  *mutationRate,
+		MutationScale: *mutationScale,
+		Seed:          *seed,
+		Opponents:     strings.Split(*opponents, ","),
+		Game:          game.GameConfig{TargetScore: *targetScore},
+	}
+
+	var checkpoint *simulator.EvolveCheckpoint
+	if *resume != "" {
+		var err error
+		checkpoint, err = simulator.LoadEvolveCheckpoint(*resume)
+		if err != nil {
+			return err
+		}
+		config = checkpoint.Config
+		config.Generations = *generations
+		fmt.Printf("Resuming from generation %d of %s\n", checkpoint.Generation, *resume)
+	} else {
+		deck, err := game.DeckSpecByName(*deckName)
+		if err != nil {
+			return err
+		}
+		config.Deck = deck
+	}
+	if config.Weights != len(algorithms.GenomeFeatures) {
+		return fmt.Errorf("checkpoint genomes have %d weights, want %d", config.Weights, len(algorithms.GenomeFeatures))
+	}
+
+	for i, opponent := range config.Opponents {
+		config.Opponents[i] = strings.TrimSpace(opponent)
+	}
+	opponentFactories, err := algorithms.Factories(config.Opponents)
+	if err != nil {
+		return err
+	}
+	config.NewOpponents = opponentFactories
+	config.Workers = *workers
+	config.NewAlgorithm = func(weights []float64) game.Algorithm {
+		return algorithms.NewWeightedAlgorithm(&algorithms.Genome{Name: *name, Weights: weights})
+	}
+
+	if err := os.MkdirAll(*checkpoints, 0o755); err != nil {
+		return err
+	}
+
+	fmt.Printf("Evolving %d genomes against %s, %d games each\n", config.Population, strings.Join(config.Opponents, ", "), config.Games)
+	fmt.Printf("Features: %s\n\n", strings.Join(algorithms.GenomeFeatures, ", "))
+
+	final, err := simulator.Evolve(config, checkpoint, func(checkpoint *simulator.EvolveCheckpoint) error {
+		stats := checkpoint.History[len(checkpoint.History)-1]
+		fmt.Printf("Generation %3d: best %5.1f%%  mean %5.1f%%  weights %s\n",
+			stats.Generation, stats.Best*100, stats.Mean*100, formatWeights(checkpoint.Best().Weights))
+
+		path := filepath.Join(*checkpoints, fmt.Sprintf("generation-%04d.json", checkpoint.Generation))
+		return checkpoint.Save(path)
+	})
+	if err != nil {
+		return err
+	}
+
+	best := final.Best()
+	genome := &algorithms.Genome{
+		Name:     *name,
+		Features: algorithms.GenomeFeatures,
+		Weights:  best.Weights,
+		Fitness:  best.Fitness,
+	}
+	if err := genome.Save(*outFile); err != nil {
+		return err
+	}
+
+	fmt.Printf("\nCheckpoints are in %s\n", *checkpoints)
+	fmt.Printf("Wrote the best genome (%.1f%% wins) to %s\n", best.Fitness*100, *outFile)
+	fmt.Printf("Play it with: flip7-simulator -genome %s\n", *outFile)
+	return nil
+}
+
+// formatWeights prints weights compactly, e.g. "[+1.20 -3.05]"
+func formatWeights(weights []float64) string {
+	formatted := make([]string, len(weights))
+	for i, weight := range weights {
+		formatted[i] = fmt.Sprintf("%+.2f", weight)
+	}
+	return "[" + strings.Join(formatted, " ") + "]"
+}
@@ -1,16 +1,61 @@
 package main
 
 import (
+	"bufio"
 	"flag"
 	"flip7-simulator/internal/algorithms"
 	"flip7-simulator/internal/game"
 	"flip7-simulator/internal/simulator"
 	"fmt"
+	"io"
+	"os"
 )
 
 func main() {
+	// Subcommands come before the simulation flags
+	subcommands := map[string]func([]string) error{
+		"replay": runReplay,
+		"play":   runPlay,
+		"solve":  runSolve,
+		"evolve": runEvolve,
+	}
+	if len(os.Args) > 1 && subcommands[os.Args[1]] != nil {
+		if err := subcommands[os.Args[1]](os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Command line flags
 	numGames := flag.Int("games", 1000, "Number of games to simulate")
+	deckName := flag.String("deck", "official", "Deck composition to play with (official, legacy)")
+	seatMode := flag.String("seats", "fixed", "How the opening dealer is chosen each game (fixed, cycle, random)")
+	tieBreakerName := flag.String("tiebreak", "shared", "How ties for the highest score are settled (shared, flip7s, comeback, extra-round)")
+	targetScore := flag.Int("target", 200, "Score that ends the game at the end of the round it is reached")
+	maxRounds := flag.Int("max-rounds", 0, "End the game after this many rounds even if nobody reached the target (0 = no limit)")
+	fixedRounds := flag.Int("rounds", 0, "Play exactly this many rounds and the highest total wins (0 = play to the target)")
+	persistDeck := flag.Bool("persist-deck", false, "Keep the deck and discard pile across rounds, reshuffling only when the deck runs out")
+	duplicate := flag.Bool("duplicate", false, "Replay every deal with the algorithms rotated through every seat, so each is judged on the same cards")
+	seed := flag.Int64("seed", 0, "Master seed for the run; the same seed gives identical results (0 = pick one from the clock)")
+	workers := flag.Int("workers", 0, "Number of games to play in parallel (0 = one per CPU)")
+	replayGame := flag.Int("replay-game", -1, "Replay only this game number of a seeded run, printing every round")
+	ratingsFile := flag.String("ratings", "", "Rate the algorithms on a multiplayer Elo ladder kept in this JSON file, creating it if needed")
+	freezeRatings := flag.Bool("freeze-ratings", false, "Keep the ratings already in the -ratings file fixed and only rate algorithms new to it")
+	tableSize := flag.Int("table", 0, "Play a round-robin tournament with every combination of this many algorithms at a table; -games is per table (0 = everyone at one table)")
+	candidate := flag.String("candidate", "", "Play this algorithm head-to-head against -baseline with a sequential probability ratio test")
+	baseline := flag.String("baseline", "Adaptive", "Algorithm the -candidate is tested against")
+	elo0 := flag.Float64("elo0", 0, "SPRT null hypothesis: the candidate is this many Elo stronger than the baseline")
+	elo1 := flag.Float64("elo1", 20, "SPRT alternative hypothesis: the candidate is this many Elo stronger than the baseline")
+	alpha := flag.Float64("alpha", 0.05, "SPRT chance of accepting elo1 when elo0 is true")
+	beta := flag.Float64("beta", 0.05, "SPRT chance of accepting elo0 when elo1 is true")
+	outputFormat := flag.String("output", "table", "Results format: table, json or csv")
+	outFile := flag.String("out", "", "Write json or csv results to this file instead of stdout")
+	eventsFile := flag.String("events", "", "Write every game event, with what each algorithm saw at each decision, to this JSON Lines file")
+	eventSample := flag.Float64("event-sample", 1, "Fraction of games written to the -events file, e.g. 0.001 for one game in a thousand")
+	policyFile := flag.String("policy", "", "Add the Win Probability algorithm to the field, playing the policy table in this file (see the solve subcommand)")
+	genomeFile := flag.String("genome", "", "Add a weighted strategy to the field, playing the genome in this file under its own name (see the evolve subcommand)")
+	regret := flag.Bool("regret", false, "Score every decision against optimal single-round play and report the expected points each algorithm gives up (slow: a decision with a Second Chance held can take a second to score)")
 	help := flag.Bool("help", false, "Show help message")
 	flag.Parse()
 
@@ -25,24 +70,395 @@ func main() {
 		fmt.Println("  - Conservative: Uses risk assessment based on cards seen")
 		fmt.Println("  - Aggressive: Aggressively goes for Flip 7")
 		fmt.Println("  - Adaptive: Adapts strategy based on opponents' scores")
+		fmt.Println("  - Optimal Round: Maximizes expected round score, solved exactly")
+		fmt.Println("  - Win Probability: Plays a solved policy table to maximize the chance of winning (-policy)")
+		fmt.Println("  - Monte Carlo N [budget]: Plays N sampled deck orders out to the end of the game after hitting and after standing")
+		fmt.Println("  - ISMCTS N [budget]: Tree search over every player's moves for the rest of the round, N iterations per decision")
+		fmt.Println("  - Evolved: Weighted-feature strategy found by the evolve subcommand (-genome)")
+		fmt.Println("\nPlay a game yourself against the algorithms:")
+		fmt.Println("  play -opponents \"Conservative,Adaptive\"")
+		fmt.Println("\nSolve a win-probability policy table and play it:")
+		fmt.Println("  solve -out policy.json -opponents 3")
+		fmt.Println("  -policy policy.json -table 4")
+		fmt.Println("\nEvolve a weighted strategy and play it:")
+		fmt.Println("  evolve -generations 30 -out genome.json")
+		fmt.Println("  -genome genome.json")
+		fmt.Println("\nReplay a game from an -events log:")
+		fmt.Println("  replay games.jsonl -game 17 -verify")
+		fmt.Println("\nTournament of every 3-player table:")
+		fmt.Println("  -table 3 -games 1000")
+		fmt.Println("\nHead-to-head test:")
+		fmt.Println("  -candidate \"Stop at 30\" -baseline Adaptive -elo0 0 -elo1 20")
 		return
 	}
 
-	fmt.Println("=== Flip 7 Simulator ===")
-	fmt.Printf("Running %d games...\n\n", *numGames)
+	// Flags the user set explicitly, so head-to-head mode can pick its own defaults
+	setFlags := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) { setFlags[f.Name] = true })
+
+	deck, err := game.DeckSpecByName(*deckName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	tieBreaker, err := game.TieBreakerByName(*tieBreakerName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	gameConfig := game.GameConfig{
+		TargetScore: *targetScore,
+		MaxRounds:   *maxRounds,
+		FixedRounds: *fixedRounds,
+	}
+	if err := gameConfig.Validate(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	// A persistent deck is only shuffled from the seed once, so later rounds would differ between seat rotations
+	if *duplicate && *persistDeck {
+		fmt.Fprintln(os.Stderr, "-duplicate cannot be combined with -persist-deck")
+		os.Exit(1)
+	}
 
-	// Create different algorithms
-	algoList := []game.Algorithm{
-		algorithms.NewAlwaysHitAlgorithm(),
-		algorithms.NewStopAtScoreAlgorithm(25),
-		algorithms.NewStopAtScoreAlgorithm(30),
-		algorithms.NewStopAtScoreAlgorithm(40),
-		algorithms.NewConservativeAlgorithm(),
-		algorithms.NewAggressiveAlgorithm(),
-		algorithms.NewAdaptiveAlgorithm(),
+	if *seatMode != "fixed" && *seatMode != "cycle" && *seatMode != "random" {
+		fmt.Fprintf(os.Stderr, "unknown seat mode %q (want fixed, cycle or random)\n", *seatMode)
+		os.Exit(1)
 	}
 
+	// Create a factory for each algorithm so every worker gets its own instances
+	names := algorithms.DefaultField
+	if *candidate != "" {
+		names = []string{*candidate, *baseline}
+
+		// Alternate who acts first so neither side gets the seat advantage
+		if !setFlags["seats"] {
+			*seatMode = "cycle"
+		}
+	}
+
+	// Algorithms loaded from files, which join the field unless it is a head-to-head test
+	loaded, policy, err := loadFactories(*policyFile, *genomeFile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if *candidate == "" {
+		names = append([]string(nil), names...)
+		for _, factory := range loaded {
+			names = append(names, factory().GetName())
+		}
+	}
+
+	algoFactories, err := fieldFactories(names, loaded)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	// Optimal Round and regret scoring solve rounds exactly, which only some decks allow
+	needsSolver := *regret
+	for _, name := range names {
+		needsSolver = needsSolver || algorithms.SameName(name, "Optimal Round") || algorithms.SameName(name, "Optimal")
+	}
+	if needsSolver {
+		if err := algorithms.CheckRoundSolvable(deck); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
+	// Score decisions against the optimal single-round policy
+	var regrets *algorithms.RegretTally
+	if *regret {
+		regrets = algorithms.NewRegretTally()
+		algoFactories = regrets.WrapFactories(algoFactories)
+	}
+
+	if *tableSize != 0 && (*tableSize < 2 || *tableSize > len(names)) {
+		fmt.Fprintf(os.Stderr, "table size must be between 2 and %d\n", len(names))
+		os.Exit(1)
+	}
+
+	// A policy table is only right for the game it was solved for
+	if policy != nil {
+		players := len(names)
+		if *tableSize > 0 {
+			players = *tableSize
+		}
+		if err := policy.Check(deck, gameConfig.TargetScore, players-1); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
+	if *candidate != "" && (*alpha <= 0 || *alpha >= 1 || *beta <= 0 || *beta >= 1) {
+		fmt.Fprintln(os.Stderr, "alpha and beta must be between 0 and 1")
+		os.Exit(1)
+	}
+	if *candidate != "" && *elo1 <= *elo0 {
+		fmt.Fprintln(os.Stderr, "elo1 must be greater than elo0")
+		os.Exit(1)
+	}
+
+	if *outputFormat != "table" && *outputFormat != "json" && *outputFormat != "csv" {
+		fmt.Fprintf(os.Stderr, "unknown output format %q (want table, json or csv)\n", *outputFormat)
+		os.Exit(1)
+	}
+
+	if *outputFormat != "table" && (*tableSize > 0 || *candidate != "" || *replayGame >= 0) {
+		fmt.Fprintln(os.Stderr, "-output json and csv apply to a standard run only")
+		os.Exit(1)
+	}
+
+	// Machine-readable results on stdout push everything else to stderr
+	console := io.Writer(os.Stdout)
+	if *outputFormat != "table" && *outFile == "" {
+		console = os.Stderr
+	}
+
+	var ratings *simulator.Ratings
+	if *ratingsFile != "" {
+		ratings, err = simulator.LoadRatings(*ratingsFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if *freezeRatings {
+			ratings.Freeze()
+		}
+	}
+
+	if *eventSample <= 0 || *eventSample > 1 {
+		fmt.Fprintln(os.Stderr, "event sample rate must be above 0 and at most 1")
+		os.Exit(1)
+	}
+
+	var eventLog *simulator.EventLog
+	var events *eventLogFile
+	if *eventsFile != "" {
+		events, err = createEventLog(*eventsFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		eventLog = events.log
+	}
+
+	// finish closes the event log and exits non-zero if the run or writing the log failed
+	finish := func(err error) {
+		if events != nil {
+			if closeErr := events.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
+	fmt.Fprintln(console, "=== Flip 7 Simulator ===")
+
 	// Run simulation
-	sim := simulator.NewSimulator(algoList, *numGames)
-	sim.Run()
+	sim := simulator.NewSimulator(algoFactories, simulator.Config{
+		NumGames:       *numGames,
+		Deck:           deck,
+		PersistentDeck: *persistDeck,
+		SeatMode:       *seatMode,
+		TieBreaker:     tieBreaker,
+		Game:           gameConfig,
+		Seed:           *seed,
+		Workers:        *workers,
+		Duplicate:      *duplicate,
+		Ratings:        ratings,
+		Output:         console,
+		EventLog:       eventLog,
+		EventSample:    *eventSample,
+	})
+
+	if *replayGame >= 0 {
+		sim.ReplayGame(*replayGame)
+		finish(nil)
+		return
+	}
+
+	if *candidate != "" {
+		// -games caps the test only when given; otherwise play until a verdict or the default cap
+		maxGames := 0
+		if setFlags["games"] {
+			maxGames = *numGames
+		}
+
+		_, err := sim.RunSPRT(simulator.SPRTConfig{
+			Elo0:     *elo0,
+			Elo1:     *elo1,
+			Alpha:    *alpha,
+			Beta:     *beta,
+			MaxGames: maxGames,
+		})
+		if err == nil {
+			printRegret(console, algoFactories, regrets)
+		}
+		finish(err)
+		return
+	}
+
+	if *tableSize > 0 {
+		fmt.Fprintf(console, "Running %d games at every %d-player table...\n\n", *numGames, *tableSize)
+		if _, err := sim.RunTournament(*tableSize); err != nil {
+			finish(err)
+		}
+	} else {
+		fmt.Fprintf(console, "Running %d games...\n\n", sim.NumGames())
+		results := sim.Run()
+
+		if err := writeResults(results, *outputFormat, *outFile); err != nil {
+			finish(err)
+		}
+	}
+
+	printRegret(console, algoFactories, regrets)
+
+	if ratings != nil {
+		if err := ratings.Save(*ratingsFile); err != nil {
+			finish(err)
+		}
+		fmt.Fprintf(console, "Ratings saved to %s\n", *ratingsFile)
+	}
+
+	finish(nil)
+}
+
+// eventLogFile is an -events log buffered on its way to a file
+type eventLogFile struct {
+	log      *simulator.EventLog
+	buffered *bufio.Writer
+	file     *os.File
+}
+
+// createEventLog creates the file for an -events log
+func createEventLog(path string) (*eventLogFile, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	buffered := bufio.NewWriter(file)
+	return &eventLogFile{log: simulator.NewEventLog(buffered), buffered: buffered, file: file}, nil
+}
+
+// Close flushes and closes the log, returning the first error hit writing,
+// flushing or closing it, so a log cut short is never mistaken for a whole one
+func (f *eventLogFile) Close() error {
+	err := f.log.Err()
+	if err == nil {
+		err = f.buffered.Flush()
+	}
+	if closeErr := f.file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("writing event log %s: %w", f.file.Name(), err)
+	}
+	return nil
+}
+
+// loadFactories returns a factory for the algorithm played from each file
+// given, a Win Probability policy table and a weighted genome, along with the
+// policy table itself
+func loadFactories(policyFile, genomeFile string) ([]func() game.Algorithm, *algorithms.PolicyTable, error) {
+	var loaded []func() game.Algorithm
+	var policy *algorithms.PolicyTable
+	if policyFile != "" {
+		var err error
+		policy, err = algorithms.LoadPolicyTable(policyFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		loaded = append(loaded, func() game.Algorithm {
+			return algorithms.NewWinProbabilityAlgorithm(policy)
+		})
+	}
+	if genomeFile != "" {
+		genome, err := algorithms.LoadGenome(genomeFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		loaded = append(loaded, func() game.Algorithm {
+			return algorithms.NewWeightedAlgorithm(genome)
+		})
+	}
+	return loaded, policy, nil
+}
+
+// fieldFactories returns a factory for each named algorithm, preferring an
+// algorithm loaded from a file, such as a policy table or genome, of that name
+func fieldFactories(names []string, loaded []func() game.Algorithm) ([]func() game.Algorithm, error) {
+	factories := make([]func() game.Algorithm, len(names))
+names:
+	for i, name := range names {
+		for _, factory := range loaded {
+			if algorithms.SameName(name, factory().GetName()) {
+				factories[i] = factory
+				continue names
+			}
+		}
+
+		factory, err := algorithms.Factory(name)
+		if err != nil {
+			return nil, err
+		}
+		factories[i] = factory
+	}
+	return factories, nil
+}
+
+// printRegret reports how much expected round score each algorithm gave up
+// against optimal single-round play, if decisions were being scored
+func printRegret(w io.Writer, factories []func() game.Algorithm, regrets *algorithms.RegretTally) {
+	if regrets == nil {
+		return
+	}
+
+	fmt.Fprintf(w, "\n=== Expected Round Score Given Up vs Optimal Round Play ===\n")
+	fmt.Fprintf(w, "%-20s %10s %10s %14s\n", "Algorithm", "Decisions", "Mistakes", "EV lost/dec")
+	for _, factory := range factories {
+		name := factory().GetName()
+		regret := regrets.Get(name)
+		fmt.Fprintf(w, "%-20s %10d %9.1f%% %14.3f\n",
+			name, regret.Decisions, regret.MistakeRate()*100, regret.PerDecision())
+	}
+}
+
+// writeResults writes json or csv results to a file, or to stdout if no file is given
+func writeResults(results simulator.RunResults, format, path string) error {
+	if format == "table" {
+		return nil
+	}
+
+	if path == "" {
+		return exportResults(os.Stdout, results, format)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := exportResults(file, results, format); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// exportResults writes results as CSV or JSON
+func exportResults(out io.Writer, results simulator.RunResults, format string) error {
+	if format == "csv" {
+		return simulator.WriteCSV(out, results)
+	}
+	return simulator.WriteJSON(out, results)
 }
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"flip7-simulator/internal/game"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// narrator is an event sink that prints a game's events as they happen
+type narrator struct {
+	out    io.Writer
+	names  []string     // Algorithm names by player ID
+	busted map[int]bool // Players who bust this round
+}
+
+// newNarrator creates a narrator for a table of the named players
+func newNarrator(out io.Writer, names []string) *narrator {
+	return &narrator{out: out, names: names, busted: make(map[int]bool)}
+}
+
+// player labels a player with their algorithm's name
+func (n *narrator) player(id int) string {
+	return fmt.Sprintf("Player %d (%s)", id, n.names[id])
+}
+
+// Emit prints one event
+func (n *narrator) Emit(event game.Event) {
+	switch event.Type {
+	case "round_start":
+		n.busted = make(map[int]bool)
+		fmt.Fprintf(n.out, "\n=== Round %d, Dealer: Player %d ===\n", event.Round, *event.Dealer)
+	case "card_dealt":
+		fmt.Fprintf(n.out, "%s draws %s\n", n.player(*event.Player), event.Card)
+	case "hit", "stand":
+		if event.Observation == nil {
+			fmt.Fprintf(n.out, "%s stands (%s)\n", n.player(*event.Player), event.Reason)
+			return
+		}
+		obs := event.Observation
+		fmt.Fprintf(n.out, "%s %ss holding %s with %d cards unseen\n",
+			n.player(*event.Player), event.Type, formatCards(obs.Self().Cards), obs.UnseenCount())
+	case "action":
+		fmt.Fprintf(n.out, "%s plays %s on %s\n", n.player(*event.Player), event.Card, n.player(*event.Target))
+	case "second_chance":
+		fmt.Fprintf(n.out, "%s uses a Second Chance to discard %s\n", n.player(*event.Player), event.Card)
+	case "bust":
+		n.busted[*event.Player] = true
+		fmt.Fprintf(n.out, "%s busts on %s\n", n.player(*event.Player), event.Card)
+	case "flip7":
+		fmt.Fprintf(n.out, "%s flips 7!\n", n.player(*event.Player))
+	case "round_scored":
+		fmt.Fprintf(n.out, "--- Round %d scored ---\n", event.Round)
+		for id := range n.names {
+			hand := "BUST"
+			if !n.busted[id] {
+				hand = fmt.Sprintf("Cards: %s (Score: %d)", formatCards(event.Hands[id]), event.Scores[id])
+			}
+			fmt.Fprintf(n.out, "%s: %s, total %d\n", n.player(id), hand, event.GameScores[id])
+		}
+	case "game_over":
+		fmt.Fprintf(n.out, "\n=== Game over after %d rounds ===\n", event.Round)
+		for _, winner := range event.Winners {
+			fmt.Fprintf(n.out, "Winner: %s with %d points\n", n.player(winner), event.GameScores[winner])
+		}
+	}
+}
+
+// formatCards lists cards the way PrintGameState shows them
+func formatCards(cards []game.Card) string {
+	if len(cards) == 0 {
+		return "no cards"
+	}
+
+	shown := make([]string, len(cards))
+	for i, card := range cards {
+		shown[i] = card.String()
+	}
+	return strings.Join(shown, " ")
+}
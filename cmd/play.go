@@ -0,0 +1,86 @@
+package main
+
+import (
+	"flag"
+	"flip7-simulator/internal/algorithms"
+	"flip7-simulator/internal/game"
+	"flip7-simulator/internal/simulator"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// runPlay seats a human at a table of algorithms, reading their decisions from stdin
+func runPlay(args []string) error {
+	flags := flag.NewFlagSet("play", flag.ExitOnError)
+	opponents := flags.String("opponents", "Conservative,Adaptive,Stop at 25", "Comma-separated algorithms to play against")
+	seat := flags.Int("seat", 0, "Your seat at the table (0 acts first in the opening round)")
+	name := flags.String("name", "You", "Your name at the table")
+	deckName := flags.String("deck", "official", "Deck composition to play with (official, legacy)")
+	targetScore := flags.Int("target", 200, "Score that ends the game at the end of the round it is reached")
+	seed := flags.Int64("seed", 0, "Seed for the shuffles (0 = pick one from the clock)")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: flip7-simulator play [-opponents \"Conservative,Adaptive\"] [-seat N]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	deck, err := game.DeckSpecByName(*deckName)
+	if err != nil {
+		return err
+	}
+
+	gameConfig := game.GameConfig{TargetScore: *targetScore}
+	if err := gameConfig.Validate(); err != nil {
+		return err
+	}
+
+	var bots []game.Algorithm
+	for _, opponent := range strings.Split(*opponents, ",") {
+		bot, err := algorithms.New(strings.TrimSpace(opponent))
+		if err != nil {
+			return err
+		}
+		bots = append(bots, bot)
+	}
+
+	numPlayers := len(bots) + 1
+	if *seat < 0 || *seat >= numPlayers {
+		return fmt.Errorf("seat must be between 0 and %d", numPlayers-1)
+	}
+
+	// Seat the human among the bots
+	human := algorithms.NewHumanAlgorithm(*name, os.Stdin, os.Stdout)
+	seated := make([]game.Algorithm, 0, numPlayers)
+	seated = append(seated, bots[:*seat]...)
+	seated = append(seated, human)
+	seated = append(seated, bots[*seat:]...)
+
+	names := make([]string, numPlayers)
+	for i, algo := range seated {
+		names[i] = algo.GetName()
+	}
+	human.SetPlayerNames(names)
+
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+
+	g := game.NewSeededGame(numPlayers, deck, *seed)
+	g.Algorithms = seated
+	g.Config = gameConfig
+	g.Events = newNarrator(os.Stdout, names)
+
+	fmt.Println("=== Flip 7 ===")
+	fmt.Printf("Seed: %d\n", *seed)
+	fmt.Printf("Game: %s\n", gameConfig)
+	for id, playerName := range names {
+		fmt.Printf("Player %d: %s\n", id, playerName)
+	}
+
+	simulator.PlayRounds(g, false, nil)
+	return nil
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"flip7-simulator/internal/algorithms"
+	"flip7-simulator/internal/game"
+	"flip7-simulator/internal/simulator"
+	"fmt"
+	"os"
+)
+
+// runReplay walks through a game from an event log round by round, optionally
+// re-simulating it from its seed to check the engine still plays it identically
+func runReplay(args []string) error {
+	flags := flag.NewFlagSet("replay", flag.ExitOnError)
+	gameNum := flags.Int("game", -1, "Game number to replay (default: the first game in the log)")
+	verify := flags.Bool("verify", false, "Re-simulate the game from its seed and check the engine reproduces the log exactly")
+	policyFile := flags.String("policy", "", "Policy table the logged Win Probability seat played, for -verify")
+	genomeFile := flags.String("genome", "", "Genome a logged weighted seat played, for -verify")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: flip7-simulator replay <events.jsonl> [-game N] [-verify [-policy FILE] [-genome FILE]]")
+		flags.PrintDefaults()
+	}
+
+	files, err := parseInterspersed(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(files) != 1 {
+		flags.Usage()
+		return fmt.Errorf("replay needs exactly one event log file")
+	}
+
+	file, err := os.Open(files[0])
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	events, err := simulator.ReadGameEvents(file, *gameNum)
+	if err != nil {
+		return err
+	}
+
+	printReplay(events)
+
+	if *verify {
+		loaded, _, err := loadFactories(*policyFile, *genomeFile)
+		if err != nil {
+			return err
+		}
+		return verifyReplay(events, loaded)
+	}
+	return nil
+}
+
+// parseInterspersed parses flags that may come before or after positional
+// arguments, returning the positional arguments
+func parseInterspersed(flags *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+		if flags.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, flags.Arg(0))
+		args = flags.Args()[1:]
+	}
+}
+
+// printReplay prints a logged game round by round: every card, decision and
+// action, then each player's hand and score when the round is scored
+func printReplay(events []game.Event) {
+	start := events[0]
+	names := start.Algorithms
+
+	fmt.Printf("=== Replay of Game %d (seed %d) ===\n", start.Game, start.Seed)
+	fmt.Printf("Game: %s\n", start.Config)
+	fmt.Printf("Deck: %s (%d cards)", start.Deck.Name, start.Deck.Size())
+	if start.Persistent {
+		fmt.Printf(", persistent across rounds")
+	}
+	fmt.Printf("\n")
+	fmt.Printf("Tie-breaker: %s\n", start.TieBreaker)
+	for id, name := range names {
+		fmt.Printf("Player %d: %s\n", id, name)
+	}
+
+	narrator := newNarrator(os.Stdout, names)
+	for _, event := range events[1:] {
+		narrator.Emit(event)
+	}
+}
+
+// verifyReplay re-simulates a logged game from its seed with fresh instances of
+// the logged algorithms and checks every event matches the log. Algorithms
+// played from a file are rebuilt from the loaded factories; a game with an
+// algorithm whose decisions depend on the time it is given cannot be verified.
+func verifyReplay(events []game.Event, loaded []func() game.Algorithm) error {
+	start := events[0]
+
+	algos := make([]game.Algorithm, len(start.Algorithms))
+	for i, name := range start.Algorithms {
+		factories, err := fieldFactories([]string{name}, loaded)
+		if err != nil {
+			return fmt.Errorf("cannot verify: player %d's algorithm %q cannot be rebuilt (pass the -policy or -genome file it played): %w", i, name, err)
+		}
+		algos[i] = factories[0]()
+		if !algorithms.Reproducible(algos[i]) {
+			return fmt.Errorf("cannot verify: player %d's algorithm %q searches against a time budget, so its decisions depend on how fast the machine is", i, name)
+		}
+	}
+
+	replayed, err := simulator.ReplayEvents(start, algos)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(events) || i < len(replayed); i++ {
+		if i >= len(events) || i >= len(replayed) {
+			return fmt.Errorf("verify failed: the log has %d events but the engine produced %d", len(events), len(replayed))
+		}
+
+		logged, err := json.Marshal(events[i])
+		if err != nil {
+			return err
+		}
+		played, err := json.Marshal(replayed[i])
+		if err != nil {
+			return err
+		}
+		if string(logged) != string(played) {
+			return fmt.Errorf("verify failed at event %d:\n  logged:   %s\n  replayed: %s", i, logged, played)
+		}
+	}
+
+	fmt.Printf("\nVerified: re-simulating from seed %d reproduced all %d events\n", start.Seed, len(events))
+	return nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"flag"
+	"flip7-simulator/internal/algorithms"
+	"flip7-simulator/internal/game"
+	"fmt"
+	"time"
+)
+
+// runSolve solves the whole-game win-probability policy, writes the table to a
+// file and reads it back to check it round trips
+func runSolve(args []string) error {
+	flags := flag.NewFlagSet("solve", flag.ExitOnError)
+	outFile := flags.String("out", "policy.json", "File to write the policy table to")
+	deckName := flags.String("deck", "official", "Deck composition to solve for (official, legacy)")
+	targetScore := flags.Int("target", 200, "Score that ends the game")
+	opponents := flags.Int("opponents", 1, "Number of opponents at the table")
+	bucket := flags.Int("bucket", algorithms.DefaultPolicyBucket, "Solve game totals on a grid this many points apart")
+	samples := flags.Int("samples", algorithms.DefaultPolicySamples, "Sample hands dealt to estimate how a hand grows with each card")
+	seed := flags.Int64("seed", 1, "Seed for dealing the sample hands")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: flip7-simulator solve [-out policy.json] [-opponents N] [-target 200]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	deck, err := game.DeckSpecByName(*deckName)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Solving for %d opponent(s), playing to %d with the %s deck...\n", *opponents, *targetScore, deck.Name)
+	start := time.Now()
+	table, err := algorithms.SolveWinPolicy(algorithms.PolicyConfig{
+		Deck:      deck,
+		Target:    *targetScore,
+		Opponents: *opponents,
+		Bucket:    *bucket,
+		Samples:   *samples,
+		Seed:      *seed,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Solved in %.1fs\n", time.Since(start).Seconds())
+
+	if err := table.Save(*outFile); err != nil {
+		return err
+	}
+	loaded, err := algorithms.LoadPolicyTable(*outFile)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %s and read it back (%d grid points)\n", *outFile, len(loaded.WinChance))
+
+	// A few sample positions to show what the policy thinks, with every opponent on the same total
+	fmt.Printf("\nChance of winning from the start of a round:\n")
+	fmt.Printf("%-15s", "You \\ Opponents")
+	totals := []int{0, 50, 100, 150, 190}
+	for _, leaderTotal := range totals {
+		fmt.Printf("%8d", leaderTotal)
+	}
+	fmt.Printf("\n")
+	for _, total := range totals {
+		fmt.Printf("%-15d", total)
+		for _, leaderTotal := range totals {
+			fmt.Printf("%7.1f%%", loaded.WinProbability(total, leaderTotal, leaderTotal)*100)
+		}
+		fmt.Printf("\n")
+	}
+
+	fmt.Printf("\nPlay it with: flip7-simulator -policy %s\n", *outFile)
+	return nil
+}
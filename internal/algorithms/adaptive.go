@@ -6,12 +6,14 @@ import (
 
 // AdaptiveAlgorithm adapts strategy based on opponents' scores
 type AdaptiveAlgorithm struct {
-	name string
+	name             string
+	secondChanceCost float64 // Share of a bust a duplicate counts as while holding a Second Chance; see bustChance
 }
 
 func NewAdaptiveAlgorithm() *AdaptiveAlgorithm {
 	return &AdaptiveAlgorithm{
-		name: "Adaptive",
+		name:             "Adaptive",
+		secondChanceCost: 0.5, // Halfway between the careful and aggressive strategies
 	}
 }
 
@@ -79,22 +81,11 @@ func (a *AdaptiveAlgorithm) MakeDecision(playerState game.PlayerState, gameState
 	}
 
 	// Calculate bust risk
-	safeCards := 0
-	totalCards := 0
-
-	for value, count := range cardsRemaining {
-		totalCards += count
-		if !uniqueValues[value] {
-			safeCards += count
-		}
-	}
-
-	if totalCards == 0 {
+	bustRisk, ok := bustChance(playerState, cardsRemaining, a.secondChanceCost)
+	if !ok {
 		return game.Decision{Action: "stand"}
 	}
 
-	bustRisk := float64(totalCards-safeCards) / float64(totalCards)
-
 	if bustRisk > 0.6 {
 		return game.Decision{Action: "stand"}
 	}
@@ -102,6 +93,10 @@ func (a *AdaptiveAlgorithm) MakeDecision(playerState game.PlayerState, gameState
 	return game.Decision{Action: "hit"}
 }
 
+func (a *AdaptiveAlgorithm) ChooseTarget(playerState game.PlayerState, gameState game.GameState, card game.Card, candidates []int) int {
+	return chooseDefaultTarget(playerState, gameState, card, candidates)
+}
+
 func (a *AdaptiveAlgorithm) GetName() string {
 	return a.name
 }
@@ -6,12 +6,14 @@ import (
 
 // AggressiveAlgorithm goes for Flip 7 more aggressively
 type AggressiveAlgorithm struct {
-	name string
+	name             string
+	secondChanceCost float64 // Share of a bust a duplicate counts as while holding a Second Chance; see bustChance
 }
 
 func NewAggressiveAlgorithm() *AggressiveAlgorithm {
 	return &AggressiveAlgorithm{
-		name: "Aggressive",
+		name:             "Aggressive",
+		secondChanceCost: 0.25, // A duplicate saved by a Second Chance barely slows it down
 	}
 }
 
@@ -44,22 +46,11 @@ func (a *AggressiveAlgorithm) MakeDecision(playerState game.PlayerState, gameSta
 	}
 
 	// Calculate bust risk
-	safeCards := 0
-	totalCards := 0
-
-	for value, count := range cardsRemaining {
-		totalCards += count
-		if !uniqueValues[value] {
-			safeCards += count
-		}
-	}
-
-	if totalCards == 0 {
+	bustRisk, ok := bustChance(playerState, cardsRemaining, a.secondChanceCost)
+	if !ok {
 		return game.Decision{Action: "stand"}
 	}
 
-	bustRisk := float64(totalCards-safeCards) / float64(totalCards)
-
 	// More aggressive thresholds
 	if currentScore >= 45 && bustRisk > 0.4 {
 		return game.Decision{Action: "stand"}
@@ -72,6 +63,10 @@ func (a *AggressiveAlgorithm) MakeDecision(playerState game.PlayerState, gameSta
 	return game.Decision{Action: "hit"}
 }
 
+func (a *AggressiveAlgorithm) ChooseTarget(playerState game.PlayerState, gameState game.GameState, card game.Card, candidates []int) int {
+	return chooseDefaultTarget(playerState, gameState, card, candidates)
+}
+
 func (a *AggressiveAlgorithm) GetName() string {
 	return a.name
 }
@@ -0,0 +1,444 @@
+package algorithms
+
+import (
+	"bytes"
+	"flip7-simulator/internal/game"
+	"math"
+	"math/rand"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// bruteRoundValue returns the expected round score of a hand played optimally,
+// trying every order the unseen number and modifier cards could come in
+func bruteRoundValue(player game.PlayerState, unseen []game.Card) float64 {
+	return math.Max(float64(player.RoundScore()), bruteRoundHit(player, unseen))
+}
+
+// bruteRoundHit returns the expected round score of drawing one of the unseen
+// cards and then playing optimally
+func bruteRoundHit(player game.PlayerState, unseen []game.Card) float64 {
+	if len(unseen) == 0 {
+		return float64(player.RoundScore())
+	}
+
+	total := 0.0
+	for i, card := range unseen {
+		rest := append(append([]game.Card(nil), unseen[:i]...), unseen[i+1:]...)
+
+		next := player
+		next.Cards = append([]game.Card(nil), player.Cards...)
+		if card.CardType == "number" && player.UniqueValues()[card.Value] {
+			if !player.HasSecondChance() {
+				continue // Bust
+			}
+			// The Second Chance and the duplicate are both discarded
+			for j, held := range next.Cards {
+				if held.CardType == "action" && held.Action == "second_chance" {
+					next.Cards = append(next.Cards[:j], next.Cards[j+1:]...)
+					break
+				}
+			}
+		} else {
+			next.Cards = append(next.Cards, card)
+		}
+
+		if len(next.UniqueValues()) == 7 {
+			total += float64(next.RoundScore())
+			continue
+		}
+		total += bruteRoundValue(next, rest)
+	}
+	return total / float64(len(unseen))
+}
+
+func TestRoundValuesMatchBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for trial := 0; trial < 60; trial++ {
+		// A small deck with a hand dealt from it, leaving at most 8 cards that change the hand
+		spec := game.DeckSpec{
+			Name:      "small",
+			Numbers:   make(map[int]int),
+			Modifiers: make(map[int]int),
+			X2:        rng.Intn(2),
+			Actions:   map[string]int{"freeze": 1, "second_chance": 1},
+		}
+		values := rng.Perm(13)[:5+rng.Intn(4)]
+		for _, value := range values {
+			spec.Numbers[value] = 1 + rng.Intn(2)
+		}
+		for i := rng.Intn(3); i > 0; i-- {
+			spec.Modifiers[2*(1+rng.Intn(5))]++
+		}
+
+		var hand []game.Card
+		for _, value := range values[:1+rng.Intn(4)] {
+			hand = append(hand, game.Card{Value: value, CardType: "number"})
+		}
+		if rng.Intn(2) == 0 {
+			hand = append(hand, game.Card{CardType: "action", Action: "second_chance"})
+		}
+
+		// Cards left to draw, without the hand and the action cards the solver ignores
+		var unseen []game.Card
+		remaining := append([]game.Card(nil), hand...)
+	cards:
+		for _, card := range spec.Cards() {
+			for i, held := range remaining {
+				if held == card {
+					remaining = append(remaining[:i], remaining[i+1:]...)
+					continue cards
+				}
+			}
+			if card.CardType != "action" {
+				unseen = append(unseen, card)
+			}
+		}
+		if len(unseen) > 8 {
+			trial--
+			continue
+		}
+
+		g := game.NewSeededGame(2, spec, 1)
+		g.Players[0].Cards = hand
+		stand, hit, err := RoundValues(g.Observe(0))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		player := g.Players[0]
+		if want := float64(player.RoundScore()); stand != want {
+			t.Errorf("Hand %v: expected standing to be worth %.4f, got %.4f", hand, want, stand)
+		}
+		if want := bruteRoundHit(player, unseen); math.Abs(hit-want) > 1e-9 {
+			t.Errorf("Hand %v with %v unseen: expected hitting to be worth %.6f, got %.6f", hand, unseen, want, hit)
+		}
+	}
+}
+
+func TestRoundValuesRejectDecksBeyondTheSolver(t *testing.T) {
+	if err := CheckRoundSolvable(game.OfficialDeck()); err != nil {
+		t.Errorf("Expected the official deck to be solvable, got %v", err)
+	}
+	if err := CheckRoundSolvable(game.LegacyDeck()); err != nil {
+		t.Errorf("Expected the legacy deck to be solvable, got %v", err)
+	}
+
+	tooMany := game.OfficialDeck()
+	tooMany.Modifiers = map[int]int{1: 1, 2: 1, 3: 1, 4: 1, 5: 1, 6: 1, 7: 1, 8: 1, 9: 1}
+	tooCopied := game.OfficialDeck()
+	tooCopied.Modifiers = map[int]int{2: 4}
+
+	for _, deck := range []game.DeckSpec{tooMany, tooCopied} {
+		if err := CheckRoundSolvable(deck); err == nil {
+			t.Errorf("Expected modifiers %v to be rejected", deck.Modifiers)
+		}
+
+		g := game.NewSeededGame(2, deck, 1)
+		if _, _, err := RoundValues(g.Observe(0)); err == nil {
+			t.Errorf("Expected RoundValues to reject modifiers %v", deck.Modifiers)
+		}
+	}
+}
+
+func TestSolveWinPolicy(t *testing.T) {
+	for opponents := 1; opponents <= 2; opponents++ {
+		table, err := SolveWinPolicy(PolicyConfig{
+			Deck:      game.OfficialDeck(),
+			Target:    60,
+			Opponents: opponents,
+			Bucket:    10,
+			Samples:   20000,
+			Seed:      1,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Playing to win can only beat the opponents' even share, but not by a lot
+		fair := 1 / float64(opponents+1)
+		if start := table.WinProbability(0, 0, 0); start < fair || start > fair+0.15 {
+			t.Errorf("%d opponent(s): expected a win chance a little above %.3f at the start, got %.3f", opponents, fair, start)
+		}
+
+		// Being further ahead never hurts
+		for total := 10; total < 60; total += 10 {
+			if table.WinProbability(total, 20, 0) < table.WinProbability(total-10, 20, 0) {
+				t.Errorf("%d opponent(s): win chance falls from %d to %d points", opponents, total-10, total)
+			}
+		}
+
+		// Far behind near the end it gambles; far ahead it banks
+		if !table.Hit(0, 50, 0, 20, 3, false) {
+			t.Errorf("%d opponent(s): expected to hit on 20 points when far behind", opponents)
+		}
+		if table.Hit(50, 0, 0, 20, 3, false) {
+			t.Errorf("%d opponent(s): expected to stand on 20 points when 20 would win", opponents)
+		}
+	}
+}
+
+func TestPolicyTableRoundTrip(t *testing.T) {
+	table, err := SolveWinPolicy(PolicyConfig{
+		Deck:      game.OfficialDeck(),
+		Target:    40,
+		Opponents: 2,
+		Bucket:    10,
+		Samples:   5000,
+		Seed:      2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "policy.json")
+	if err := table.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadPolicyTable(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(table, loaded) {
+		t.Error("Expected the loaded table to match the saved one")
+	}
+
+	if err := loaded.Check(game.OfficialDeck(), 40, 2); err != nil {
+		t.Errorf("Expected the table to fit the game it was solved for, got %v", err)
+	}
+	if err := loaded.Check(game.LegacyDeck(), 40, 2); err == nil {
+		t.Error("Expected a table solved for another deck to be rejected")
+	}
+	if err := loaded.Check(game.OfficialDeck(), 200, 2); err == nil {
+		t.Error("Expected a table solved for another target to be rejected")
+	}
+	if err := loaded.Check(game.OfficialDeck(), 40, 3); err == nil {
+		t.Error("Expected a table solved for another number of opponents to be rejected")
+	}
+
+	// A table whose grid does not fit its settings is rejected
+	table.Opponents = 1
+	if err := table.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadPolicyTable(path); err == nil {
+		t.Error("Expected a table with the wrong grid size to be rejected")
+	}
+}
+
+// roundStart returns what the player to move sees at the start of a
+// two-player round, with them and their opponent holding the given hands and
+// game scores, and the given cards already in the discard pile
+func roundStart(t *testing.T, self, opponent game.PlayerState, discards []game.Card) game.Observation {
+	g := game.NewSeededGame(2, game.OfficialDeck(), 1)
+	g.StartNewRound()
+	g.CreateDeck()
+	g.BeginRound()
+	if len(g.LegalMoves()) != 2 {
+		t.Fatalf("Expected a hit or stand to start the round, got %v", g.LegalMoves())
+	}
+
+	id := g.ToMove()
+	self.ID, opponent.ID = id, 1-id
+	g.Players[id], g.Players[1-id] = self, opponent
+	g.DiscardPile = discards
+
+	return g.Observe(id)
+}
+
+// numbers returns a number card of each value
+func numbers(values ...int) []game.Card {
+	cards := make([]game.Card, len(values))
+	for i, value := range values {
+		cards[i] = game.Card{Value: value, CardType: "number"}
+	}
+	return cards
+}
+
+func TestMonteCarloTakesTheObviousAction(t *testing.T) {
+	tests := []struct {
+		name     string
+		self     game.PlayerState
+		opponent game.PlayerState
+		want     string
+	}{
+		{
+			// 57 points win the game unless a hit busts, which most cards would do
+			name:     "big hand near the target",
+			self:     game.PlayerState{Cards: numbers(12, 11, 10, 9, 8, 7), GameScore: 160},
+			opponent: game.PlayerState{Cards: numbers(5), GameScore: 100},
+			want:     "stand",
+		},
+		{
+			// The opponent has already stood on enough to win, so standing certainly loses
+			name:     "behind an opponent who has won",
+			self:     game.PlayerState{Cards: numbers(3), GameScore: 180},
+			opponent: game.PlayerState{Cards: numbers(10, 5), GameScore: 190, HasStood: true},
+			want:     "hit",
+		},
+		{
+			// Two points are worth little and one more card can hardly bust them
+			name:     "small hand early in the game",
+			self:     game.PlayerState{Cards: numbers(2)},
+			opponent: game.PlayerState{Cards: numbers(6)},
+			want:     "hit",
+		},
+	}
+
+	monteCarlo := NewMonteCarloAlgorithm(MonteCarloConfig{Rollouts: 200})
+	for _, tt := range tests {
+		if got := monteCarlo.MakeDecision(roundStart(t, tt.self, tt.opponent, nil)).Action; got != tt.want {
+			t.Errorf("%s: expected to %s, got %s", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestGenomeRoundTrip(t *testing.T) {
+	genome := &Genome{
+		Name:     "Evolved",
+		Features: GenomeFeatures,
+		Weights:  []float64{1.5, -4, -0.75, 0.25, 2, 0},
+		Fitness:  0.42,
+	}
+
+	path := filepath.Join(t.TempDir(), "genome.json")
+	if err := genome.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadGenome(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(genome, loaded) {
+		t.Errorf("Expected the loaded genome to match the saved one, got %+v", loaded)
+	}
+}
+
+func TestLoadGenomeRejectsMismatchedWeights(t *testing.T) {
+	renamed := append([]string(nil), GenomeFeatures...)
+	renamed[1] = "bust_chance"
+	weights := make([]float64, len(GenomeFeatures))
+
+	tests := []struct {
+		name   string
+		genome Genome
+	}{
+		{"unknown feature", Genome{Name: "Evolved", Features: renamed, Weights: weights}},
+		{"missing weight", Genome{Name: "Evolved", Features: GenomeFeatures, Weights: weights[1:]}},
+		{"extra weight", Genome{Name: "Evolved", Features: GenomeFeatures, Weights: append(weights, 1)}},
+		{"missing feature", Genome{Name: "Evolved", Features: GenomeFeatures[:5], Weights: weights[:5]}},
+		{"no name", Genome{Features: GenomeFeatures, Weights: weights}},
+	}
+
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "genome.json")
+		if err := tt.genome.Save(path); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadGenome(path); err == nil {
+			t.Errorf("%s: expected the genome to be rejected", tt.name)
+		}
+	}
+}
+
+func TestHumanAsksAgainUntilAnswered(t *testing.T) {
+	g := game.NewSeededGame(3, game.OfficialDeck(), 1)
+	obs := g.Observe(0)
+	freeze := game.Card{CardType: "action", Action: "freeze"}
+
+	var out bytes.Buffer
+	human := NewHumanAlgorithm("You", strings.NewReader("maybe\nH\n7\n0\n 2 \n"), &out)
+
+	if decision := human.MakeDecision(obs); decision.Action != "hit" {
+		t.Errorf("Expected to hit after an invalid answer, got %s", decision.Action)
+	}
+	if !strings.Contains(out.String(), "Please type h to hit or s to stand.") {
+		t.Error("Expected an invalid answer to be prompted again")
+	}
+
+	// Player 7 is not at the table and player 0 is not a candidate
+	if target := human.ChooseTarget(obs, freeze, []int{1, 2}); target != 2 {
+		t.Errorf("Expected to target player 2, got %d", target)
+	}
+	if strings.Count(out.String(), "Please pick one of the players listed.") != 2 {
+		t.Error("Expected each target that is not a candidate to be prompted again")
+	}
+}
+
+func TestHumanFallsBackAtEndOfInput(t *testing.T) {
+	g := game.NewSeededGame(3, game.OfficialDeck(), 1)
+	obs := g.Observe(0)
+	freeze := game.Card{CardType: "action", Action: "freeze"}
+
+	human := NewHumanAlgorithm("You", strings.NewReader(""), &bytes.Buffer{})
+
+	if decision := human.MakeDecision(obs); decision.Action != "stand" {
+		t.Errorf("Expected to stand once the input runs out, got %s", decision.Action)
+	}
+	if target := human.ChooseTarget(obs, freeze, []int{2, 1}); target != 2 {
+		t.Errorf("Expected the first candidate once the input runs out, got %d", target)
+	}
+}
+
+func TestISMCTSTakesTheObviousAction(t *testing.T) {
+	ismcts := NewISMCTSAlgorithm(ISMCTSConfig{Iterations: 300})
+
+	// Everything but the duplicates of the hand is in the discard pile, so any hit busts
+	var discards []game.Card
+	for _, card := range game.OfficialDeck().Cards() {
+		if (card.CardType != "number" || card.Value < 7) && card != numbers(5)[0] {
+			discards = append(discards, card)
+		}
+	}
+	full := roundStart(t, game.PlayerState{Cards: numbers(12, 11, 10, 9, 8, 7)}, game.PlayerState{Cards: numbers(5)}, discards)
+	if decision := ismcts.MakeDecision(full); decision.Action != "stand" {
+		t.Errorf("Expected to stand when any hit busts, got %s", decision.Action)
+	}
+
+	// With no number cards held, a hit cannot bust
+	empty := roundStart(t, game.PlayerState{}, game.PlayerState{Cards: numbers(6)}, nil)
+	if decision := ismcts.MakeDecision(empty); decision.Action != "hit" {
+		t.Errorf("Expected to hit on an empty hand, got %s", decision.Action)
+	}
+}
+
+func TestISMCTSFreezesTheLeader(t *testing.T) {
+	g := game.NewSeededGame(2, game.OfficialDeck(), 1)
+	g.StartNewRound()
+	g.Deck = []game.Card{
+		{Value: 0, CardType: "number"},
+		{CardType: "action", Action: "freeze"},
+		{Value: 8, CardType: "number"},
+	}
+	g.BeginRound()
+
+	card, ok := g.ChoiceCard()
+	if g.ToMove() != 1 || !ok || card.Action != "freeze" {
+		t.Fatalf("Expected player 1 to choose a Freeze target, got player %d with %v", g.ToMove(), g.LegalMoves())
+	}
+
+	// Freezing the leader on 0 points stops them closing in on the target;
+	// freezing themselves banks nothing and leaves the leader to play on
+	g.Players[0].GameScore = 150
+	g.Players[1].GameScore = 100
+
+	ismcts := NewISMCTSAlgorithm(ISMCTSConfig{Iterations: 300})
+	if target := ismcts.ChooseTarget(g.Observe(1), card, []int{0, 1}); target != 0 {
+		t.Errorf("Expected to freeze the leader, got player %d", target)
+	}
+}
+
+func TestISMCTSIsReproducible(t *testing.T) {
+	for _, hand := range [][]int{{12, 5}, {9, 4, 2}, {11, 8, 6, 3}} {
+		obs := roundStart(t, game.PlayerState{Cards: numbers(hand...), GameScore: 80}, game.PlayerState{Cards: numbers(7), GameScore: 95}, nil)
+
+		first, _ := NewISMCTSAlgorithm(ISMCTSConfig{Iterations: 200}).search(obs)
+		second, _ := NewISMCTSAlgorithm(ISMCTSConfig{Iterations: 200}).search(obs)
+		if first != second {
+			t.Errorf("Hand %v: expected the same move from the same observation, got %s and %s", hand, first, second)
+		}
+	}
+}
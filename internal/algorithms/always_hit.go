@@ -19,6 +19,10 @@ func (a *AlwaysHitAlgorithm) MakeDecision(playerState game.PlayerState, gameStat
 	return game.Decision{Action: "hit"}
 }
 
+func (a *AlwaysHitAlgorithm) ChooseTarget(playerState game.PlayerState, gameState game.GameState, card game.Card, candidates []int) int {
+	return chooseDefaultTarget(playerState, gameState, card, candidates)
+}
+
 func (a *AlwaysHitAlgorithm) GetName() string {
 	return a.name
 }
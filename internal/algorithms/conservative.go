@@ -6,12 +6,14 @@ import (
 
 // ConservativeAlgorithm uses risk assessment based on cards seen
 type ConservativeAlgorithm struct {
-	name string
+	name             string
+	secondChanceCost float64 // Share of a bust a duplicate counts as while holding a Second Chance; see bustChance
 }
 
 func NewConservativeAlgorithm() *ConservativeAlgorithm {
 	return &ConservativeAlgorithm{
-		name: "Conservative",
+		name:             "Conservative",
+		secondChanceCost: 0.6, // Losing the Second Chance is most of a bust to a careful player
 	}
 }
 
@@ -43,18 +45,8 @@ func (a *ConservativeAlgorithm) MakeDecision(playerState game.PlayerState, gameS
 		return game.Decision{Action: "hit"}
 	}
 
-	// Calculate risk of busting
-	safeCards := 0
-	totalCards := 0
-
-	for value, count := range cardsRemaining {
-		totalCards += count
-		if !uniqueValues[value] {
-			safeCards += count
-		}
-	}
-
-	bustRisk := float64(totalCards-safeCards) / float64(totalCards)
+	// Calculate risk of busting; with no number cards left there is none
+	bustRisk, _ := bustChance(playerState, cardsRemaining, a.secondChanceCost)
 
 	// Conservative thresholds
 	if currentScore >= 35 && bustRisk > 0.3 {
@@ -68,6 +60,10 @@ func (a *ConservativeAlgorithm) MakeDecision(playerState game.PlayerState, gameS
 	return game.Decision{Action: "hit"}
 }
 
+func (a *ConservativeAlgorithm) ChooseTarget(playerState game.PlayerState, gameState game.GameState, card game.Card, candidates []int) int {
+	return chooseDefaultTarget(playerState, gameState, card, candidates)
+}
+
 func (a *ConservativeAlgorithm) GetName() string {
 	return a.name
 }
@@ -0,0 +1,132 @@
+package algorithms
+
+import (
+	"bufio"
+	"flip7-simulator/internal/game"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// HumanAlgorithm lets a person play a seat, showing the table and reading
+// each decision from a prompt
+type HumanAlgorithm struct {
+	name    string
+	names   []string // Name of every player at the table, by player ID
+	scanner *bufio.Scanner
+	out     io.Writer
+}
+
+// NewHumanAlgorithm creates a human seat that reads answers from in and writes the table and prompts to out
+func NewHumanAlgorithm(name string, in io.Reader, out io.Writer) *HumanAlgorithm {
+	return &HumanAlgorithm{
+		name:    name,
+		scanner: bufio.NewScanner(in),
+		out:     out,
+	}
+}
+
+// SetPlayerNames tells the human who is sitting in each seat
+func (a *HumanAlgorithm) SetPlayerNames(names []string) {
+	a.names = append([]string(nil), names...)
+}
+
+func (a *HumanAlgorithm) MakeDecision(obs game.Observation) game.Decision {
+	a.showTable(obs)
+
+	for {
+		answer, ok := a.ask("Hit or stand? [h/s]: ")
+		if !ok {
+			// Out of input: bank what we have
+			return game.Decision{Action: "stand"}
+		}
+
+		switch strings.ToLower(answer) {
+		case "h", "hit":
+			return game.Decision{Action: "hit"}
+		case "s", "stand":
+			return game.Decision{Action: "stand"}
+		}
+		fmt.Fprintf(a.out, "Please type h to hit or s to stand.\n")
+	}
+}
+
+func (a *HumanAlgorithm) ChooseTarget(obs game.Observation, card game.Card, candidates []int) int {
+	a.showTable(obs)
+	fmt.Fprintf(a.out, "You drew %s. Who do you play it on?\n", card)
+	for _, id := range candidates {
+		fmt.Fprintf(a.out, "  %d: %s\n", id, a.playerName(obs, id))
+	}
+
+	for {
+		answer, ok := a.ask("Player number: ")
+		if !ok {
+			return candidates[0]
+		}
+
+		if target, err := strconv.Atoi(answer); err == nil {
+			for _, id := range candidates {
+				if id == target {
+					return target
+				}
+			}
+		}
+		fmt.Fprintf(a.out, "Please pick one of the players listed.\n")
+	}
+}
+
+func (a *HumanAlgorithm) GetName() string {
+	return a.name
+}
+
+// ask prompts for a line of input, returning false once the input runs out
+func (a *HumanAlgorithm) ask(prompt string) (string, bool) {
+	fmt.Fprintf(a.out, "%s", prompt)
+	if !a.scanner.Scan() {
+		fmt.Fprintf(a.out, "\n")
+		return "", false
+	}
+	return strings.TrimSpace(a.scanner.Text()), true
+}
+
+// showTable prints every player's face-up cards and scores and how many cards are unseen
+func (a *HumanAlgorithm) showTable(obs game.Observation) {
+	fmt.Fprintf(a.out, "\n--- Round %d, your turn ---\n", obs.Round())
+
+	for _, player := range obs.Players() {
+		status := ""
+		if player.IsBust {
+			status = " BUST"
+		} else if player.HasStood {
+			status = " stood"
+		}
+
+		cards := make([]string, len(player.Cards))
+		for i, card := range player.Cards {
+			cards[i] = card.String()
+		}
+
+		fmt.Fprintf(a.out, "%-28s %-24s round %3d  game %3d%s\n",
+			a.playerName(obs, player.ID),
+			strings.Join(cards, " "),
+			player.RoundScore(),
+			player.GameScore,
+			status)
+	}
+
+	fmt.Fprintf(a.out, "Cards left: %d unseen (%d in the discard pile), playing to %s\n",
+		obs.UnseenCount(), len(obs.DiscardPile()), obs.Config())
+}
+
+// playerName labels a player for the table, marking the human's own seat
+func (a *HumanAlgorithm) playerName(obs game.Observation, playerID int) string {
+	name := fmt.Sprintf("Player %d", playerID)
+	if playerID < len(a.names) {
+		name += " (" + a.names[playerID] + ")"
+	}
+	if playerID == obs.PlayerID() {
+		name += " <- you"
+	}
+	return name
+}
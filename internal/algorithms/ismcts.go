@@ -0,0 +1,265 @@
+package algorithms
+
+import (
+	"flip7-simulator/internal/game"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Default settings for the ISMCTS algorithm
+const (
+	DefaultISMCTSIterations  = 1000
+	DefaultISMCTSExploration = 0.7
+)
+
+// ISMCTSConfig sets how much searching the ISMCTS algorithm does per decision
+type ISMCTSConfig struct {
+	Iterations  int           // Searches from a fresh deck order per decision
+	Budget      time.Duration // Stop searching once a decision has taken this long; 0 = no limit
+	Exploration float64       // How strongly the search tries moves it has played little
+	Policy      string        // Algorithm every player follows once a search leaves the tree
+	Seed        int64         // Mixed into each decision's seed for sampling deck orders
+}
+
+// ISMCTSAlgorithm decides by Information Set Monte Carlo Tree Search over the
+// rest of the round. Each iteration deals the unseen cards into a random deck
+// order and walks a tree of every player's upcoming decisions, hits and stands
+// as well as Freeze, Flip Three and Second Chance targets. Each player picks
+// their move by UCB on their own share of the wins, so opponents are expected
+// to play well rather than by any fixed rule. Once it reaches a move it has
+// not tried, the round and then the game are played out with a simple default
+// policy. A move leads to a different node for each set of cards it reveals,
+// so the tree branches on what the players can see and no more. It takes the
+// move it searched most often. Like Monte Carlo it is seeded from the
+// observation, so without a time budget a game replays exactly.
+type ISMCTSAlgorithm struct {
+	name   string
+	config ISMCTSConfig
+	policy *rolloutPolicy
+}
+
+// NewISMCTSAlgorithm creates an ISMCTS algorithm, filling in defaults for unset config fields
+func NewISMCTSAlgorithm(config ISMCTSConfig) *ISMCTSAlgorithm {
+	if config.Iterations <= 0 {
+		config.Iterations = DefaultISMCTSIterations
+	}
+	if config.Exploration <= 0 {
+		config.Exploration = DefaultISMCTSExploration
+	}
+	if config.Policy == "" {
+		config.Policy = DefaultRolloutPolicy
+	}
+
+	name := fmt.Sprintf("ISMCTS %d", config.Iterations)
+	if config.Budget > 0 {
+		name += " " + config.Budget.String()
+	}
+
+	return &ISMCTSAlgorithm{
+		name:   name,
+		config: config,
+		policy: &rolloutPolicy{name: config.Policy},
+	}
+}
+
+func (a *ISMCTSAlgorithm) MakeDecision(obs game.Observation) game.Decision {
+	if move, ok := a.search(obs); ok && move.Action == "hit" {
+		return game.Decision{Action: "hit"}
+	}
+	return game.Decision{Action: "stand"}
+}
+
+func (a *ISMCTSAlgorithm) ChooseTarget(obs game.Observation, card game.Card, candidates []int) int {
+	if move, ok := a.search(obs); ok && move.Action == "target" {
+		for _, candidate := range candidates {
+			if candidate == move.Target {
+				return candidate
+			}
+		}
+	}
+	return chooseDefaultTarget(obs.Self(), game.GameState{Players: obs.Players()}, card, candidates)
+}
+
+func (a *ISMCTSAlgorithm) GetName() string {
+	return a.name
+}
+
+// searchNode is a decision reached by a sequence of moves and the cards they revealed
+type searchNode struct {
+	edges []*searchEdge // Moves tried from this node, in the order they were first legal
+}
+
+// searchEdge is a move from a node and how it has fared
+type searchEdge struct {
+	move      game.Move
+	player    int                    // Player making the move
+	visits    int                    // Iterations that played the move
+	available int                    // Iterations in which the move was legal
+	wins      float64                // Share of the wins for the player making the move, over its visits
+	children  map[uint64]*searchNode // Nodes the move led to, by what the players could see afterwards
+}
+
+// edge returns the node's edge for a player's move, adding it the first time the move is legal
+func (n *searchNode) edge(move game.Move, player int) *searchEdge {
+	for _, edge := range n.edges {
+		if edge.move == move && edge.player == player {
+			return edge
+		}
+	}
+
+	edge := &searchEdge{move: move, player: player, children: make(map[uint64]*searchNode)}
+	n.edges = append(n.edges, edge)
+	return edge
+}
+
+// child returns the node a move led to when it revealed what key describes
+func (e *searchEdge) child(key uint64) *searchNode {
+	node, ok := e.children[key]
+	if !ok {
+		node = &searchNode{}
+		e.children[key] = node
+	}
+	return node
+}
+
+// search runs the tree search from the decision the observation was made
+// for, returning the move played most often from the root. It reports false
+// if the observation is not one a decision can be searched from.
+func (a *ISMCTSAlgorithm) search(obs game.Observation) (game.Move, bool) {
+	policy, err := a.policy.seats(obs.NumPlayers())
+	if err != nil {
+		return game.Move{}, false
+	}
+
+	rng := rand.New(rand.NewSource(observationSeed(obs, a.config.Seed)))
+	root := &searchNode{}
+
+	start := time.Now()
+	for i := 0; i < a.config.Iterations; i++ {
+		if a.config.Budget > 0 && i > 0 && time.Since(start) >= a.config.Budget {
+			break
+		}
+
+		g := obs.Determinize(rng)
+		if g.ToMove() != obs.PlayerID() {
+			return game.Move{}, false
+		}
+		g.Algorithms = policy
+		a.iterate(g, root, rng)
+	}
+
+	var best *searchEdge
+	for _, edge := range root.edges {
+		if best == nil || edge.visits > best.visits {
+			best = edge
+		}
+	}
+	if best == nil {
+		return game.Move{}, false
+	}
+	return best.move, true
+}
+
+// iterate walks the tree from the root on one determinized game, adds the
+// first move it has not tried, plays the game out and credits every move on
+// the way with the share of the wins of the player who made it
+func (a *ISMCTSAlgorithm) iterate(g *game.Game, root *searchNode, rng *rand.Rand) {
+	var path []*searchEdge
+	node := root
+
+	for g.ToMove() >= 0 {
+		player := g.ToMove()
+
+		// Only the moves legal in this deal count towards how often each was available
+		var legal, untried []*searchEdge
+		for _, move := range g.LegalMoves() {
+			edge := node.edge(move, player)
+			edge.available++
+			legal = append(legal, edge)
+			if edge.visits == 0 {
+				untried = append(untried, edge)
+			}
+		}
+
+		if len(untried) > 0 {
+			edge := untried[rng.Intn(len(untried))]
+			g.ApplyMove(edge.move)
+			path = append(path, edge)
+			break
+		}
+
+		edge := a.selectEdge(legal)
+		g.ApplyMove(edge.move)
+		path = append(path, edge)
+		node = edge.child(publicKey(g))
+	}
+
+	g.FinishRound()
+	g.FinishGame()
+
+	for _, edge := range path {
+		edge.visits++
+		edge.wins += winShare(g, edge.player)
+	}
+}
+
+// selectEdge picks the move with the best upper confidence bound on its win
+// share, counting how often each move was available rather than how often
+// the node was visited, since different deals allow different moves
+func (a *ISMCTSAlgorithm) selectEdge(edges []*searchEdge) *searchEdge {
+	var best *searchEdge
+	bestBound := math.Inf(-1)
+	for _, edge := range edges {
+		visits := float64(edge.visits)
+		bound := edge.wins/visits + a.config.Exploration*math.Sqrt(math.Log(float64(edge.available))/visits)
+		if bound > bestBound {
+			best, bestBound = edge, bound
+		}
+	}
+	return best
+}
+
+// publicKey hashes what every player can see of the round: the cards in front
+// of each player, who has bust or stood, the discard pile and who is to move
+func publicKey(g *game.Game) uint64 {
+	hash := uint64(14695981039346656037)
+	mix := func(value int) {
+		hash ^= uint64(value)
+		hash *= 1099511628211
+	}
+	mixCard := func(card game.Card) {
+		mix(card.Value)
+		mix(card.Modifier)
+		for _, text := range []string{card.CardType, card.Action} {
+			for i := 0; i < len(text); i++ {
+				mix(int(text[i]))
+			}
+		}
+		if card.IsX2 {
+			mix(1)
+		}
+	}
+
+	for _, player := range g.Players {
+		mix(len(player.Cards))
+		for _, card := range player.Cards {
+			mixCard(card)
+		}
+		if player.IsBust {
+			mix(2)
+		}
+		if player.HasStood {
+			mix(3)
+		}
+	}
+
+	mix(len(g.DiscardPile))
+	for _, card := range g.DiscardPile {
+		mixCard(card)
+	}
+
+	mix(g.ToMove())
+	return hash
+}
@@ -0,0 +1,554 @@
+package algorithms
+
+import (
+	"flip7-simulator/internal/game"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// Default settings for solving a win-probability policy
+const (
+	DefaultPolicyBucket  = 5
+	DefaultPolicySamples = 200000
+)
+
+// PolicyConfig describes the game a win-probability policy is solved for
+type PolicyConfig struct {
+	Deck      game.DeckSpec
+	Target    int   // Score that ends the game
+	Opponents int   // Number of opponents at the table
+	Bucket    int   // Game totals are solved on a grid this many points apart
+	Samples   int   // Hands dealt to estimate how a hand grows with each card
+	Seed      int64 // Seed for dealing the sample hands
+}
+
+// SolveWinPolicy treats the game as a Markov decision process over the
+// player's total, the leading opponent's total, the best total among the
+// other opponents and the player's hand, and solves it by value iteration for
+// the hit/stand policy that maximizes the chance of winning. It is an
+// approximation in a few ways:
+//   - a hand is summarized by its number total plus modifiers, how many
+//     number values it holds and whether it has an x2; how it grows with each
+//     card is estimated by dealing sample hands from a full deck
+//   - action cards are ignored
+//   - opponents play for the most points each round
+//   - with three or more opponents, the best of the others is treated as one
+//     player gaining the best of their round scores, which overstates how
+//     fast the trailing opponents catch up
+//   - game totals are kept on a grid and interpolated between points
+func SolveWinPolicy(config PolicyConfig) (*PolicyTable, error) {
+	if config.Target <= 0 {
+		return nil, fmt.Errorf("target score must be positive")
+	}
+	if config.Opponents < 1 {
+		return nil, fmt.Errorf("need at least one opponent")
+	}
+	if config.Bucket <= 0 {
+		config.Bucket = DefaultPolicyBucket
+	}
+	if config.Samples <= 0 {
+		config.Samples = DefaultPolicySamples
+	}
+
+	kernel := newHandKernel(config.Deck, config.Samples, config.Seed)
+	return newWinSolver(config, kernel).solve(), nil
+}
+
+// handKernel is how a hand changes with each card drawn. Hand states are
+// indexed by handIndex; every move goes to a higher index or ends the round.
+type handKernel struct {
+	maxSum   int          // Highest number total plus modifiers a hand of six values can hold
+	maxScore int          // Highest round score a hand can make
+	moves    [][]handMove // Possible results of a hit from each hand state
+	bust     []float64    // Chance a hit from each hand state busts
+}
+
+// handMove is one possible result of a hit: a new hand state, or a Flip 7 that ends the round
+type handMove struct {
+	next  int // Hand state after the card, or -1 for a Flip 7
+	score int // Round score of the Flip 7
+	prob  float64
+}
+
+// handIndex packs a hand's number total plus modifiers, number values held and x2 into a state index
+func handIndex(sum, values int, x2 bool) int {
+	index := (sum*7 + values) * 2
+	if x2 {
+		index++
+	}
+	return index
+}
+
+// handScore returns the round score of standing on a hand state
+func handScore(index int) int {
+	sum := index / 14
+	if index%2 == 1 {
+		return sum * 2
+	}
+	return sum
+}
+
+// newHandKernel estimates the hand kernel by dealing sample hands from a
+// shuffled full deck until they bust or Flip 7
+func newHandKernel(deck game.DeckSpec, samples int, seed int64) *handKernel {
+	var cards []game.Card
+	for _, card := range deck.Cards() {
+		if card.CardType != "action" {
+			cards = append(cards, card)
+		}
+	}
+
+	// The most a hand can hold: the six highest values and every modifier
+	var values []int
+	for value, count := range deck.Numbers {
+		if count > 0 {
+			values = append(values, value)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(values)))
+	maxSum := 0
+	for i := 0; i < len(values) && i < 6; i++ {
+		maxSum += values[i]
+	}
+	for modifier, count := range deck.Modifiers {
+		maxSum += modifier * count
+	}
+
+	states := handIndex(maxSum, 6, true) + 1
+	counts := make([]map[handMove]int, states)
+	totals := make([]int, states)
+	busts := make([]int, states)
+
+	rng := rand.New(rand.NewSource(seed))
+	for i := 0; i < samples; i++ {
+		rng.Shuffle(len(cards), func(a, b int) { cards[a], cards[b] = cards[b], cards[a] })
+
+		var held [13]bool
+		sum, numValues, x2 := 0, 0, false
+		for _, card := range cards {
+			from := handIndex(sum, numValues, x2)
+			totals[from]++
+
+			if card.CardType == "number" && held[card.Value] {
+				busts[from]++
+				break
+			}
+
+			switch {
+			case card.CardType == "number":
+				held[card.Value] = true
+				sum += card.Value
+				numValues++
+			case card.IsX2:
+				x2 = true
+			default:
+				sum += card.Modifier
+			}
+
+			move := handMove{next: handIndex(sum, numValues, x2)}
+			if numValues == 7 {
+				move = handMove{next: -1, score: handScore(move.next) + 15}
+			}
+			if counts[from] == nil {
+				counts[from] = make(map[handMove]int)
+			}
+			counts[from][move]++
+
+			if numValues == 7 {
+				break
+			}
+		}
+	}
+
+	kernel := &handKernel{
+		maxSum:   maxSum,
+		maxScore: 2*maxSum + 15,
+		moves:    make([][]handMove, states),
+		bust:     make([]float64, states),
+	}
+	for index, moves := range counts {
+		if totals[index] == 0 {
+			continue
+		}
+		total := float64(totals[index])
+		kernel.bust[index] = float64(busts[index]) / total
+		for move, count := range moves {
+			move.prob = float64(count) / total
+			kernel.moves[index] = append(kernel.moves[index], move)
+		}
+
+		// Map order is random; a fixed order keeps the sums reproducible
+		sort.Slice(kernel.moves[index], func(a, b int) bool {
+			ma, mb := kernel.moves[index][a], kernel.moves[index][b]
+			if ma.next != mb.next {
+				return ma.next < mb.next
+			}
+			return ma.score < mb.score
+		})
+	}
+
+	return kernel
+}
+
+// hitValue returns the value of hitting from a hand state, given the value of
+// standing on each round score and of playing on from each later hand state
+func (k *handKernel) hitValue(index int, stand, values []float64) float64 {
+	if len(k.moves[index]) == 0 && k.bust[index] == 0 {
+		// Never reached in the samples, so there is nothing to go on
+		return math.Inf(-1)
+	}
+
+	value := k.bust[index] * stand[0]
+	for _, move := range k.moves[index] {
+		if move.next < 0 {
+			value += move.prob * stand[move.score]
+		} else {
+			value += move.prob * values[move.next]
+		}
+	}
+	return value
+}
+
+// play finds the best hit/stand decision in every hand state given the value
+// of standing on each round score, returning the value of a round from its
+// first card, which is always dealt
+func (k *handKernel) play(stand, values []float64, hit []bool) float64 {
+	for index := len(k.moves) - 1; index >= 0; index-- {
+		standValue := stand[handScore(index)]
+		hitValue := k.hitValue(index, stand, values)
+
+		hit[index] = hitValue > standValue
+		values[index] = max(standValue, hitValue)
+	}
+	return k.hitValue(0, stand, values)
+}
+
+// roundScores returns the chance of each round score when playing the given decisions
+func (k *handKernel) roundScores(hit []bool) []float64 {
+	scores := make([]float64, k.maxScore+1)
+	reach := make([]float64, len(k.moves))
+	reach[0] = 1
+
+	for index, chance := range reach {
+		if chance == 0 {
+			continue
+		}
+		if index != 0 && !hit[index] {
+			scores[handScore(index)] += chance
+			continue
+		}
+
+		scores[0] += chance * k.bust[index]
+		for _, move := range k.moves[index] {
+			if move.next < 0 {
+				scores[move.score] += chance * move.prob
+			} else {
+				reach[move.next] += chance * move.prob
+			}
+		}
+	}
+	return scores
+}
+
+// opponentScores returns the chance of each round score for one opponent
+// playing for the most points in the round, and for the best of several
+func (k *handKernel) opponentScores(opponents int) ([]float64, []float64) {
+	stand := make([]float64, k.maxScore+1)
+	for score := range stand {
+		stand[score] = float64(score)
+	}
+	hit := make([]bool, len(k.moves))
+	k.play(stand, make([]float64, len(k.moves)), hit)
+	single := k.roundScores(hit)
+
+	// The best of several independent scores: P(best <= s) = P(one <= s)^opponents
+	best := make([]float64, len(single))
+	cumulative, previous := 0.0, 0.0
+	for score, chance := range single {
+		cumulative += chance
+		atMost := math.Pow(cumulative, float64(opponents))
+		best[score] = atMost - previous
+		previous = atMost
+	}
+	return single, best
+}
+
+// policyGrid indexes the grid of game totals a policy is solved on: the
+// player's, the leading opponent's and, with more than one opponent, the best
+// of the other opponents', which is never above the leader's
+type policyGrid struct {
+	cells  int  // Grid points per total below the target
+	others bool // Whether the other opponents' best total is tracked
+}
+
+// newPolicyGrid returns the grid for a game to the target with the given number of opponents
+func newPolicyGrid(target, bucket, opponents int) policyGrid {
+	return policyGrid{cells: (target + bucket - 1) / bucket, others: opponents > 1}
+}
+
+// opponentCells returns the number of grid points for the opponents' totals per own total
+func (g policyGrid) opponentCells() int {
+	if !g.others {
+		return g.cells
+	}
+	return g.cells * (g.cells + 1) / 2
+}
+
+// size returns the number of grid points
+func (g policyGrid) size() int {
+	return g.cells * g.opponentCells()
+}
+
+// index returns the position of a grid point; others is 0 when it is not tracked
+func (g policyGrid) index(own, leader, others int) int {
+	if !g.others {
+		return own*g.cells + leader
+	}
+	return own*g.opponentCells() + leader*(leader+1)/2 + others
+}
+
+// winSolver runs value iteration over the grid of game totals
+type winSolver struct {
+	config PolicyConfig
+	kernel *handKernel
+	grid   policyGrid
+	bucket float64
+
+	leaderCDF []float64 // Chance the leading opponent scores at most each amount in a round
+	othersCDF []float64 // Chance the best of the other opponents does, always 1 without them
+
+	leaderMoves [][]float64 // By leader cell, the chance of reaching each cell without ending the game
+	othersMoves [][]float64 // The same for the others' best total; only cell 0 without them
+
+	win     []float64 // Chance of winning from the start of a round, by grid index
+	reached []float64 // Expected win chance once the opponents' round is over, by grid index of own cell and opponents' cells before it
+}
+
+// newWinSolver works out how the opponents' totals move in a round
+func newWinSolver(config PolicyConfig, kernel *handKernel) *winSolver {
+	s := &winSolver{
+		config: config,
+		kernel: kernel,
+		grid:   newPolicyGrid(config.Target, config.Bucket, config.Opponents),
+		bucket: float64(config.Bucket),
+	}
+
+	single, others := kernel.opponentScores(config.Opponents - 1)
+	if !s.grid.others {
+		others = []float64{1}
+	}
+	s.leaderCDF, s.othersCDF = cumulative(single), cumulative(others)
+
+	othersCells := 1
+	if s.grid.others {
+		othersCells = s.grid.cells
+	}
+	s.leaderMoves = make([][]float64, s.grid.cells)
+	for cell := range s.leaderMoves {
+		s.leaderMoves[cell] = s.gridMoves(cell, single)
+	}
+	s.othersMoves = make([][]float64, othersCells)
+	for cell := range s.othersMoves {
+		s.othersMoves[cell] = s.gridMoves(cell, others)
+	}
+
+	s.win = make([]float64, s.grid.size())
+	s.reached = make([]float64, s.grid.size())
+	return s
+}
+
+// cumulative returns the running totals of a distribution
+func cumulative(chances []float64) []float64 {
+	sums := make([]float64, len(chances))
+	total := 0.0
+	for i, chance := range chances {
+		total += chance
+		sums[i] = total
+	}
+	return sums
+}
+
+// atMost returns the chance a round score from the distribution is at most gain
+func atMost(cdf []float64, gain int) float64 {
+	if gain < 0 {
+		return 0
+	}
+	return cdf[min(gain, len(cdf)-1)]
+}
+
+// gridMoves spreads a total's chance of each round score over the grid points
+// around the total it reaches, leaving out the scores that reach the target
+func (s *winSolver) gridMoves(cell int, gains []float64) []float64 {
+	moves := make([]float64, s.grid.cells)
+	for gain, chance := range gains {
+		total := cell*s.config.Bucket + gain
+		if chance == 0 || total >= s.config.Target {
+			continue
+		}
+		i, fraction := s.split(total)
+		moves[i] += chance * (1 - fraction)
+		if fraction > 0 {
+			moves[i+1] += chance * fraction
+		}
+	}
+	return moves
+}
+
+// split returns the grid point at or below a total under the target and how
+// far the total is towards the next one
+func (s *winSolver) split(total int) (int, float64) {
+	i := total / s.config.Bucket
+	if i+1 >= s.grid.cells {
+		return i, 0
+	}
+	return i, float64(total-i*s.config.Bucket) / s.bucket
+}
+
+// solve fills in the win chance and policy for every grid point. Totals only
+// go up, so grid points are solved from the top down; a point's own value
+// depends on itself through rounds where no total leaves its cell, which is
+// iterated to a fixed point.
+func (s *winSolver) solve() *PolicyTable {
+	policy := make([][]byte, s.grid.size())
+
+	states := len(s.kernel.moves)
+	base := make([]float64, s.kernel.maxScore+1)
+	weight := make([]float64, s.kernel.maxScore+1)
+	stand := make([]float64, s.kernel.maxScore+1)
+	values := make([]float64, states)
+	hit := make([]bool, states)
+
+	for own := s.grid.cells - 1; own >= 0; own-- {
+		s.forOpponents(func(leader, others int) {
+			index := s.grid.index(own, leader, others)
+			reached, self := s.opponentsRound(own, leader, others)
+
+			// The value of ending the round on each score, as base + weight * this point's own value
+			for score := range base {
+				base[score], weight[score] = s.roundEnd(own, leader, others, score, reached, self)
+			}
+
+			value := 0.5
+			for iteration := 0; iteration < 1000; iteration++ {
+				for score := range stand {
+					stand[score] = base[score] + weight[score]*value
+				}
+				next := s.kernel.play(stand, values, hit)
+				converged := math.Abs(next-value) < 1e-10
+				value = next
+				if converged {
+					break
+				}
+			}
+
+			s.win[index] = value
+			policy[index] = encodePolicy(hit)
+		})
+
+		// Later rows only need this row once the opponents have played their round
+		s.forOpponents(func(leader, others int) {
+			reached, self := s.opponentsRound(own, leader, others)
+			s.reached[s.grid.index(own, leader, others)] = reached + self*s.win[s.grid.index(own, leader, others)]
+		})
+	}
+
+	return &PolicyTable{
+		Target:    s.config.Target,
+		Opponents: s.config.Opponents,
+		Bucket:    s.config.Bucket,
+		Deck:      s.config.Deck.Name,
+		MaxSum:    s.kernel.maxSum,
+		Samples:   s.config.Samples,
+		Seed:      s.config.Seed,
+		WinChance: s.win,
+		Policy:    policy,
+	}
+}
+
+// forOpponents calls visit for every pair of opponent cells, from the top of the grid down
+func (s *winSolver) forOpponents(visit func(leader, others int)) {
+	for leader := s.grid.cells - 1; leader >= 0; leader-- {
+		if !s.grid.others {
+			visit(leader, 0)
+			continue
+		}
+		for others := leader; others >= 0; others-- {
+			visit(leader, others)
+		}
+	}
+}
+
+// opponentsRound returns the chance of winning with the player's total on a
+// grid point once the opponents have played a round that leaves the game
+// going, split into a known part and the weight it puts on the win chance of
+// the point itself
+func (s *winSolver) opponentsRound(own, leader, others int) (float64, float64) {
+	value, self := 0.0, 0.0
+	leaderMoves, othersMoves := s.leaderMoves[leader], s.othersMoves[others]
+
+	for a := leader; a < s.grid.cells; a++ {
+		if leaderMoves[a] == 0 {
+			continue
+		}
+		for b := others; b < len(othersMoves); b++ {
+			chance := leaderMoves[a] * othersMoves[b]
+			if chance == 0 {
+				continue
+			}
+			top, next := max(a, b), min(a, b)
+			if top == leader && next == others {
+				self += chance
+			} else {
+				value += chance * s.win[s.grid.index(own, top, next)]
+			}
+		}
+	}
+	return value, self
+}
+
+// roundEnd returns the chance of winning after a round the player ends on
+// the given score, split into a known part and the weight it puts on the win
+// chance of the grid point being solved. The opponents' round at the
+// player's own grid point is passed in, since it has not been stored yet.
+func (s *winSolver) roundEnd(own, leader, others, score int, reached, self float64) (float64, float64) {
+	total := own*s.config.Bucket + score
+
+	// Reaching the target wins unless an opponent ends the round higher
+	if total >= s.config.Target {
+		below := atMost(s.leaderCDF, total-1-leader*s.config.Bucket) * atMost(s.othersCDF, total-1-others*s.config.Bucket)
+		level := atMost(s.leaderCDF, total-leader*s.config.Bucket)*atMost(s.othersCDF, total-others*s.config.Bucket) - below
+		return below + level/2, 0
+	}
+
+	// Otherwise an opponent reaching the target wins, and the game goes on from the grid points around the totals
+	i, fraction := s.split(total)
+	value, weight := 0.0, 0.0
+	for _, corner := range [2]struct {
+		i      int
+		weight float64
+	}{{i, 1 - fraction}, {i + 1, fraction}} {
+		if corner.weight == 0 {
+			continue
+		}
+		if corner.i == own {
+			value += corner.weight * reached
+			weight += corner.weight * self
+		} else {
+			value += corner.weight * s.reached[s.grid.index(corner.i, leader, others)]
+		}
+	}
+	return value, weight
+}
+
+// encodePolicy packs hit/stand decisions by hand state into a bit per state, set to hit
+func encodePolicy(hit []bool) []byte {
+	policy := make([]byte, (len(hit)+7)/8)
+	for state, h := range hit {
+		if h {
+			policy[state/8] |= 1 << (state % 8)
+		}
+	}
+	return policy
+}
@@ -0,0 +1,149 @@
+package algorithms
+
+import (
+	"encoding/json"
+	"flip7-simulator/internal/game"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"time"
+)
+
+// Default settings for the Monte Carlo algorithm
+const (
+	DefaultRollouts      = 200
+	DefaultRolloutPolicy = "Stop at 25"
+)
+
+// MonteCarloConfig sets how much searching the Monte Carlo algorithm does per decision
+type MonteCarloConfig struct {
+	Rollouts int           // Rollouts of each action per decision
+	Budget   time.Duration // Stop rolling out once a decision has taken this long; 0 = no limit
+	Policy   string        // Algorithm every player follows during rollouts
+	Seed     int64         // Mixed into each decision's seed for sampling deck orders
+}
+
+// MonteCarloAlgorithm decides by sampling deck orders consistent with the
+// cards it has seen and playing each one out to the end of the game, once
+// after hitting and once after standing, with every player following a
+// simple default policy. It takes the action that won more often, or the
+// default policy's action when both won equally often. The deck orders are
+// sampled from a seed drawn from the observation, so without a time budget
+// the same situation always gets the same decision.
+type MonteCarloAlgorithm struct {
+	name   string
+	config MonteCarloConfig
+	policy *rolloutPolicy
+}
+
+// NewMonteCarloAlgorithm creates a Monte Carlo algorithm, filling in defaults for unset config fields
+func NewMonteCarloAlgorithm(config MonteCarloConfig) *MonteCarloAlgorithm {
+	if config.Rollouts <= 0 {
+		config.Rollouts = DefaultRollouts
+	}
+	if config.Policy == "" {
+		config.Policy = DefaultRolloutPolicy
+	}
+
+	name := fmt.Sprintf("Monte Carlo %d", config.Rollouts)
+	if config.Budget > 0 {
+		name += " " + config.Budget.String()
+	}
+
+	return &MonteCarloAlgorithm{
+		name:   name,
+		config: config,
+		policy: &rolloutPolicy{name: config.Policy},
+	}
+}
+
+func (a *MonteCarloAlgorithm) MakeDecision(obs game.Observation) game.Decision {
+	policy, err := a.policy.seats(obs.NumPlayers())
+	if err != nil {
+		return game.Decision{Action: "stand"}
+	}
+
+	hit := game.Move{Action: "hit"}
+	stand := game.Move{Action: "stand"}
+
+	rng := rand.New(rand.NewSource(observationSeed(obs, a.config.Seed)))
+
+	start := time.Now()
+	hitWins, standWins := 0.0, 0.0
+	for i := 0; i < a.config.Rollouts; i++ {
+		if a.config.Budget > 0 && i > 0 && time.Since(start) >= a.config.Budget {
+			break
+		}
+
+		// Both actions are played out on the same deck order, so the comparison is like for like
+		g := obs.Determinize(rng)
+		g.Algorithms = policy
+		hitWins += a.rollout(g.Clone(), obs.PlayerID(), hit)
+		standWins += a.rollout(g, obs.PlayerID(), stand)
+	}
+
+	// The rollouts often cannot tell the actions apart when the round is unlikely
+	// to change who wins; the rollout policy's own choice then stands
+	switch {
+	case hitWins > standWins:
+		return game.Decision{Action: "hit"}
+	case standWins > hitWins:
+		return game.Decision{Action: "stand"}
+	}
+	return policy[obs.PlayerID()].MakeDecision(obs)
+}
+
+func (a *MonteCarloAlgorithm) ChooseTarget(obs game.Observation, card game.Card, candidates []int) int {
+	return chooseDefaultTarget(obs.Self(), game.GameState{Players: obs.Players()}, card, candidates)
+}
+
+func (a *MonteCarloAlgorithm) GetName() string {
+	return a.name
+}
+
+// rollout plays a determinized game to the end from the player's move and
+// returns their share of the win
+func (a *MonteCarloAlgorithm) rollout(g *game.Game, playerID int, move game.Move) float64 {
+	g.ApplyMove(move)
+	g.FinishRound()
+	g.FinishGame()
+	return winShare(g, playerID)
+}
+
+// rolloutPolicy is the algorithm every player follows when a search plays a
+// game out, with one instance per seat created on first use
+type rolloutPolicy struct {
+	name      string
+	algorithm []game.Algorithm
+}
+
+// seats returns the rollout policy for each seat at a table of the given size
+func (p *rolloutPolicy) seats(numPlayers int) ([]game.Algorithm, error) {
+	for len(p.algorithm) < numPlayers {
+		algorithm, err := New(p.name)
+		if err != nil {
+			return nil, err
+		}
+		p.algorithm = append(p.algorithm, algorithm)
+	}
+	return p.algorithm[:numPlayers], nil
+}
+
+// winShare returns a player's share of the win in a finished game
+func winShare(g *game.Game, playerID int) float64 {
+	for _, winner := range g.Winners {
+		if winner == playerID {
+			return 1 / float64(len(g.Winners))
+		}
+	}
+	return 0
+}
+
+// observationSeed hashes everything in an observation into a seed
+func observationSeed(obs game.Observation, seed int64) int64 {
+	data, _ := json.Marshal(obs)
+
+	hash := fnv.New64a()
+	hash.Write(data)
+	return int64(hash.Sum64()) ^ seed
+}
@@ -0,0 +1,329 @@
+package algorithms
+
+import (
+	"flip7-simulator/internal/game"
+	"fmt"
+	"math/bits"
+	"sort"
+)
+
+// OptimalRoundAlgorithm plays each round to maximize its expected round score.
+// It solves the rest of the round exactly by dynamic programming over its own
+// future draws from the unseen cards, ignoring game score and opponents.
+type OptimalRoundAlgorithm struct {
+	name string
+}
+
+func NewOptimalRoundAlgorithm() *OptimalRoundAlgorithm {
+	return &OptimalRoundAlgorithm{
+		name: "Optimal Round",
+	}
+}
+
+// MakeDecision hits when that is worth more on average than standing. It
+// stands on a deck RoundValues cannot solve; CheckRoundSolvable catches those.
+func (a *OptimalRoundAlgorithm) MakeDecision(obs game.Observation) game.Decision {
+	stand, hit, err := RoundValues(obs)
+	if err == nil && hit > stand {
+		return game.Decision{Action: "hit"}
+	}
+	return game.Decision{Action: "stand"}
+}
+
+func (a *OptimalRoundAlgorithm) ChooseTarget(obs game.Observation, card game.Card, candidates []int) int {
+	return chooseDefaultTarget(obs.Self(), game.GameState{Players: obs.Players()}, card, candidates)
+}
+
+func (a *OptimalRoundAlgorithm) GetName() string {
+	return a.name
+}
+
+// RoundValues returns the expected round score of standing now and of hitting
+// and then playing on optimally, drawing from the observation's unseen cards.
+// Action cards still to come are treated as having no effect on the player;
+// a Second Chance they already hold saves them from their next duplicate.
+// A hand with a Second Chance held is far slower to solve, up to a second.
+func RoundValues(obs game.Observation) (float64, float64, error) {
+	solver, err := newRoundSolver(obs)
+	if err != nil {
+		return 0, 0, err
+	}
+	start := solver.start(obs.Self())
+
+	return solver.score(start, false), solver.hit(start), nil
+}
+
+// CheckRoundSolvable reports an error if rounds dealt from the deck hold more
+// cards of some kind than RoundValues can track exactly
+func CheckRoundSolvable(deck game.DeckSpec) error {
+	counts := make(map[game.Card]int)
+	for _, card := range deck.Cards() {
+		counts[card]++
+	}
+	if err := checkSolvable(counts); err != nil {
+		return fmt.Errorf("deck %s: %w", deck.Name, err)
+	}
+	return nil
+}
+
+// Limits of the unseen cards the solver can track
+const (
+	maxModifierKinds  = 8 // Distinct +N modifiers
+	maxModifierCopies = 3 // Copies of each +N modifier, and of x2
+)
+
+// checkSolvable reports cards beyond the solver's limits among the given counts
+func checkSolvable(counts map[game.Card]int) error {
+	kinds, x2 := 0, 0
+	for card, count := range counts {
+		switch {
+		case card.CardType == "number" && (card.Value < 0 || card.Value > 12):
+			return fmt.Errorf("the round solver only handles number cards 0 to 12, not %d", card.Value)
+		case card.CardType == "modifier" && card.IsX2:
+			x2 += count
+		case card.CardType == "modifier":
+			kinds++
+			if count > maxModifierCopies {
+				return fmt.Errorf("the round solver handles at most %d copies of a modifier, not %d of +%d", maxModifierCopies, count, card.Modifier)
+			}
+		}
+	}
+
+	if kinds > maxModifierKinds {
+		return fmt.Errorf("the round solver handles at most %d kinds of +N modifier, not %d", maxModifierKinds, kinds)
+	}
+	if x2 > maxModifierCopies {
+		return fmt.Errorf("the round solver handles at most %d x2 cards, not %d", maxModifierCopies, x2)
+	}
+	return nil
+}
+
+// roundState is a hand during the solver's search
+type roundState struct {
+	mask     uint16 // Number values held
+	burned   int8   // Number value discarded with the Second Chance, or -1
+	modDrawn [maxModifierKinds]uint8
+	modTotal int
+	x2Drawn  uint8
+	hasX2    bool
+	hasSC    bool
+}
+
+// key packs everything that sets a state's value into a memo key. The
+// modifier total and x2 follow from the starting hand and what has been drawn.
+func (s roundState) key() uint64 {
+	key := uint64(s.mask) | uint64(s.burned+1)<<13
+	for i, drawn := range s.modDrawn {
+		key |= uint64(drawn&3) << (17 + 2*i)
+	}
+	key |= uint64(s.x2Drawn&3) << 33
+	if s.hasSC {
+		key |= 1 << 35
+	}
+	return key
+}
+
+// roundSolver works out expected round scores from the cards unseen at a decision
+type roundSolver struct {
+	numbers   [13]int // Unseen count of each number value
+	modifiers []int   // Value of each +N modifier kind
+	modCounts []int   // Unseen count of each modifier kind
+	x2        int     // Unseen x2 cards
+	startMask uint16
+	memo      map[uint64]float64
+}
+
+// newRoundSolver counts the unseen cards that would change the player's hand
+func newRoundSolver(obs game.Observation) (*roundSolver, error) {
+	unseen := obs.Unseen()
+	if err := checkSolvable(unseen); err != nil {
+		return nil, err
+	}
+
+	solver := &roundSolver{memo: make(map[uint64]float64)}
+	modifierCounts := make(map[int]int)
+	for card, count := range unseen {
+		switch {
+		case card.CardType == "number":
+			solver.numbers[card.Value] += count
+		case card.CardType == "modifier" && card.IsX2:
+			solver.x2 += count
+		case card.CardType == "modifier":
+			modifierCounts[card.Modifier] += count
+		}
+	}
+
+	for modifier := range modifierCounts {
+		solver.modifiers = append(solver.modifiers, modifier)
+	}
+	sort.Ints(solver.modifiers)
+	for _, modifier := range solver.modifiers {
+		solver.modCounts = append(solver.modCounts, modifierCounts[modifier])
+	}
+
+	return solver, nil
+}
+
+// start builds the solver state for the player's current hand
+func (r *roundSolver) start(player game.PlayerState) roundState {
+	state := roundState{burned: -1}
+	for _, card := range player.Cards {
+		switch {
+		case card.CardType == "number":
+			state.mask |= 1 << card.Value
+		case card.CardType == "modifier" && card.IsX2:
+			state.hasX2 = true
+		case card.CardType == "modifier":
+			state.modTotal += card.Modifier
+		case card.CardType == "action" && card.Action == "second_chance":
+			state.hasSC = true
+		}
+	}
+	r.startMask = state.mask
+	return state
+}
+
+// score returns the round score of standing on a hand
+func (r *roundSolver) score(state roundState, flip7 bool) float64 {
+	total := state.modTotal
+	for values := state.mask; values != 0; values &= values - 1 {
+		total += bits.TrailingZeros16(values)
+	}
+	if state.hasX2 {
+		total *= 2
+	}
+	if flip7 {
+		total += 15
+	}
+	return float64(total)
+}
+
+// remaining returns how many cards of a number value are still unseen from the state
+func (r *roundSolver) remaining(state roundState, value int) int {
+	count := r.numbers[value]
+	bit := uint16(1) << value
+	if state.mask&bit != 0 && r.startMask&bit == 0 {
+		count--
+	}
+	if int(state.burned) == value {
+		count--
+	}
+	return count
+}
+
+// value returns the expected round score of a hand played optimally from here
+func (r *roundSolver) value(state roundState) float64 {
+	key := state.key()
+	if value, ok := r.memo[key]; ok {
+		return value
+	}
+
+	value := r.score(state, false)
+	if !r.mustStop(state, value) {
+		if hit := r.hit(state); hit > value {
+			value = hit
+		}
+	}
+
+	r.memo[key] = value
+	return value
+}
+
+// mustStop reports whether standing is certainly optimal. Without a Second
+// Chance, a hit risks losing the whole score to one of the held values'
+// remaining copies, and that risk only grows while the most any card could
+// add is bounded by what is left to draw. Once the risk outweighs every
+// possible gain, no later state can do better on average than standing now.
+func (r *roundSolver) mustStop(state roundState, stand float64) bool {
+	if state.hasSC {
+		return false
+	}
+
+	x2Left := r.x2 - int(state.x2Drawn)
+	multiplier := 1
+	if state.hasX2 || x2Left > 0 {
+		multiplier = 2
+	}
+
+	duplicates, gain, freeCards := 0, 0, 0
+	for value := 0; value < 13; value++ {
+		count := r.remaining(state, value)
+		if count <= 0 {
+			continue
+		}
+		if state.mask&(1<<value) != 0 {
+			duplicates += count
+		} else {
+			gain += count * value * multiplier
+			freeCards += count
+		}
+	}
+	for i, modifier := range r.modifiers {
+		if count := r.modCounts[i] - int(state.modDrawn[i]); count > 0 {
+			gain += count * modifier * multiplier
+		}
+	}
+	gain += 15 * freeCards
+
+	return float64(gain) <= float64(duplicates-x2Left)*stand
+}
+
+// hit returns the expected round score of drawing one more card and then playing optimally
+func (r *roundSolver) hit(state roundState) float64 {
+	total := 0
+	weighted := 0.0
+
+	for value := 0; value < 13; value++ {
+		count := r.remaining(state, value)
+		if count <= 0 {
+			continue
+		}
+		total += count
+
+		bit := uint16(1) << value
+		next := state
+		switch {
+		case state.mask&bit == 0:
+			next.mask |= bit
+			if bits.OnesCount16(next.mask) == 7 {
+				weighted += float64(count) * r.score(next, true)
+				continue
+			}
+		case state.hasSC:
+			next.hasSC = false
+			next.burned = int8(value)
+		default:
+			// Bust: the round scores nothing
+			continue
+		}
+		weighted += float64(count) * r.value(next)
+	}
+
+	for i, modifier := range r.modifiers {
+		count := r.modCounts[i] - int(state.modDrawn[i])
+		if count <= 0 {
+			continue
+		}
+		total += count
+
+		next := state
+		next.modDrawn[i]++
+		next.modTotal += modifier
+		weighted += float64(count) * r.value(next)
+	}
+
+	if count := r.x2 - int(state.x2Drawn); count > 0 {
+		total += count
+
+		next := state
+		next.x2Drawn++
+		next.hasX2 = true
+		weighted += float64(count) * r.value(next)
+	}
+
+	if total == 0 {
+		// Nothing left that changes the hand: hitting is the same as standing
+		return r.score(state, false)
+	}
+	return weighted / float64(total)
+}
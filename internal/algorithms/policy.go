@@ -0,0 +1,190 @@
+package algorithms
+
+import (
+	"encoding/json"
+	"flip7-simulator/internal/game"
+	"fmt"
+	"os"
+)
+
+// PolicyTable is a solved win-probability policy: whether to hit or stand in
+// each hand state at each point on a grid of game totals, with the chance of
+// winning from the start of a round at each grid point. The grid covers the
+// player's total, the leading opponent's and, with more than one opponent,
+// the best of the others'.
+type PolicyTable struct {
+	Target    int       `json:"target"`
+	Opponents int       `json:"opponents"`
+	Bucket    int       `json:"bucket"`
+	Deck      string    `json:"deck"`
+	MaxSum    int       `json:"max_sum"` // Highest hand total the policy covers
+	Samples   int       `json:"samples"`
+	Seed      int64     `json:"seed"`
+	WinChance []float64 `json:"win_chance"` // By grid point
+	Policy    [][]byte  `json:"policy"`     // By grid point: a bit per hand state, set to hit
+}
+
+// LoadPolicyTable reads a policy table written by Save
+func LoadPolicyTable(path string) (*PolicyTable, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var table PolicyTable
+	if err := json.Unmarshal(data, &table); err != nil {
+		return nil, fmt.Errorf("reading policy table from %s: %w", path, err)
+	}
+	if err := table.validate(); err != nil {
+		return nil, fmt.Errorf("reading policy table from %s: %w", path, err)
+	}
+
+	return &table, nil
+}
+
+// Save writes the table to a JSON file
+func (t *PolicyTable) Save(path string) error {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// validate checks the table's grid and policy fit together
+func (t *PolicyTable) validate() error {
+	if t.Target <= 0 || t.Bucket <= 0 || t.Opponents < 1 {
+		return fmt.Errorf("policy table has no grid")
+	}
+
+	size := t.grid().size()
+	if len(t.WinChance) != size {
+		return fmt.Errorf("win chance grid has %d points, want %d", len(t.WinChance), size)
+	}
+	if len(t.Policy) != size {
+		return fmt.Errorf("policy has %d grid points, want %d", len(t.Policy), size)
+	}
+
+	states := handIndex(t.MaxSum, 6, true) + 1
+	for _, policy := range t.Policy {
+		if len(policy) != (states+7)/8 {
+			return fmt.Errorf("policy grid point covers %d hand states, want %d", len(policy)*8, states)
+		}
+	}
+	return nil
+}
+
+// Check reports an error if the table was solved for a different deck, target
+// score or number of opponents
+func (t *PolicyTable) Check(deck game.DeckSpec, targetScore, opponents int) error {
+	if t.Deck != deck.Name {
+		return fmt.Errorf("policy table was solved for the %s deck, not %s", t.Deck, deck.Name)
+	}
+	if t.Target != targetScore {
+		return fmt.Errorf("policy table was solved for a target of %d, not %d", t.Target, targetScore)
+	}
+	if t.Opponents != opponents {
+		return fmt.Errorf("policy table was solved for %d opponent(s), not %d; solve it with -opponents %d", t.Opponents, opponents, opponents)
+	}
+	return nil
+}
+
+// grid returns the grid of game totals the table was solved on
+func (t *PolicyTable) grid() policyGrid {
+	return newPolicyGrid(t.Target, t.Bucket, t.Opponents)
+}
+
+// index returns the nearest grid point to the given totals
+func (t *PolicyTable) index(total, leaderTotal, othersTotal int) int {
+	grid := t.grid()
+	cell := func(total int) int {
+		return min(max((total+t.Bucket/2)/t.Bucket, 0), grid.cells-1)
+	}
+	if !grid.others {
+		othersTotal = 0
+	}
+	return grid.index(cell(total), cell(leaderTotal), cell(othersTotal))
+}
+
+// WinProbability returns the chance of winning from the start of a round with
+// the given totals: the player's, the leading opponent's and the best of the
+// other opponents', which is ignored for a table solved for one opponent
+func (t *PolicyTable) WinProbability(total, leaderTotal, othersTotal int) float64 {
+	if total >= t.Target || leaderTotal >= t.Target {
+		switch {
+		case total > leaderTotal:
+			return 1
+		case total == leaderTotal:
+			return 0.5
+		}
+		return 0
+	}
+	return t.WinChance[t.index(total, leaderTotal, othersTotal)]
+}
+
+// Hit reports whether the policy hits on a hand with the given number total
+// plus modifiers, number values held and x2, at the given game totals
+func (t *PolicyTable) Hit(total, leaderTotal, othersTotal, sum, values int, x2 bool) bool {
+	if sum < 0 || sum > t.MaxSum || values > 6 {
+		return false
+	}
+	policy := t.Policy[t.index(total, leaderTotal, othersTotal)]
+	state := handIndex(sum, values, x2)
+	return policy[state/8]&(1<<(state%8)) != 0
+}
+
+// WinProbabilityAlgorithm plays a solved PolicyTable, hitting or standing to
+// maximize its chance of winning the game rather than its score this round
+type WinProbabilityAlgorithm struct {
+	name  string
+	table *PolicyTable
+}
+
+func NewWinProbabilityAlgorithm(table *PolicyTable) *WinProbabilityAlgorithm {
+	return &WinProbabilityAlgorithm{
+		name:  "Win Probability",
+		table: table,
+	}
+}
+
+func (a *WinProbabilityAlgorithm) MakeDecision(obs game.Observation) game.Decision {
+	self := obs.Self()
+
+	// The two highest opponent totals
+	leaderTotal, othersTotal := 0, 0
+	for _, player := range obs.Players() {
+		switch {
+		case player.ID == self.ID:
+		case player.GameScore > leaderTotal:
+			leaderTotal, othersTotal = player.GameScore, leaderTotal
+		case player.GameScore > othersTotal:
+			othersTotal = player.GameScore
+		}
+	}
+
+	sum, values, x2 := 0, 0, false
+	for _, card := range self.Cards {
+		switch {
+		case card.CardType == "number":
+			sum += card.Value
+			values++
+		case card.CardType == "modifier" && card.IsX2:
+			x2 = true
+		case card.CardType == "modifier":
+			sum += card.Modifier
+		}
+	}
+
+	if a.table.Hit(self.GameScore, leaderTotal, othersTotal, sum, values, x2) {
+		return game.Decision{Action: "hit"}
+	}
+	return game.Decision{Action: "stand"}
+}
+
+func (a *WinProbabilityAlgorithm) ChooseTarget(obs game.Observation, card game.Card, candidates []int) int {
+	return chooseDefaultTarget(obs.Self(), game.GameState{Players: obs.Players()}, card, candidates)
+}
+
+func (a *WinProbabilityAlgorithm) GetName() string {
+	return a.name
+}
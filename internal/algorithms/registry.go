@@ -0,0 +1,147 @@
+package algorithms
+
+import (
+	"flip7-simulator/internal/game"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultField lists the algorithms that play in a standard simulation run
+var DefaultField = []string{
+	"Always Hit",
+	"Stop at 25",
+	"Stop at 30",
+	"Stop at 40",
+	"Conservative",
+	"Aggressive",
+	"Adaptive",
+}
+
+// Names describes the algorithm names accepted by New
+func Names() []string {
+	return []string{"Always Hit", "Stop at N", "Conservative", "Aggressive", "Adaptive", "Optimal Round", "Monte Carlo [rollouts] [time budget]", "ISMCTS [iterations] [time budget]"}
+}
+
+// New creates an algorithm from its name, e.g. "Conservative" or "Stop at 30".
+// Names are matched ignoring case, spaces, hyphens and underscores.
+func New(name string) (game.Algorithm, error) {
+	key := normalizeName(name)
+
+	switch key {
+	case "alwayshit":
+		return game.FromLegacy(NewAlwaysHitAlgorithm()), nil
+	case "conservative":
+		return game.FromLegacy(NewConservativeAlgorithm()), nil
+	case "aggressive":
+		return game.FromLegacy(NewAggressiveAlgorithm()), nil
+	case "adaptive":
+		return game.FromLegacy(NewAdaptiveAlgorithm()), nil
+	case "optimal", "optimalround":
+		return NewOptimalRoundAlgorithm(), nil
+	}
+
+	if strings.HasPrefix(key, "montecarlo") {
+		rollouts, budget, err := searchSettings(name, "montecarlo", "rollout count")
+		if err != nil {
+			return nil, err
+		}
+		return NewMonteCarloAlgorithm(MonteCarloConfig{Rollouts: rollouts, Budget: budget}), nil
+	}
+	if strings.HasPrefix(key, "ismcts") {
+		iterations, budget, err := searchSettings(name, "ismcts", "iteration count")
+		if err != nil {
+			return nil, err
+		}
+		return NewISMCTSAlgorithm(ISMCTSConfig{Iterations: iterations, Budget: budget}), nil
+	}
+
+	var target int
+	if _, err := fmt.Sscanf(key, "stopat%d", &target); err == nil && target > 0 {
+		return game.FromLegacy(NewStopAtScoreAlgorithm(target)), nil
+	}
+
+	return nil, fmt.Errorf("unknown algorithm %q (want one of: %s)", name, strings.Join(Names(), ", "))
+}
+
+// searchSettings reads the settings after the name of a search algorithm,
+// such as "Monte Carlo 500 20ms" or "ISMCTS 2000": a number sets how much
+// searching it does per decision and a duration its time budget
+func searchSettings(name, key, count string) (int, time.Duration, error) {
+	// Skip the words that spell out the name, however it was written
+	fields := strings.Fields(name)
+	prefix := ""
+	for len(fields) > 0 && prefix != key {
+		prefix += normalizeName(fields[0])
+		fields = fields[1:]
+	}
+
+	n, budget := 0, time.Duration(0)
+	for _, field := range fields {
+		if value, err := strconv.Atoi(field); err == nil && value > 0 {
+			n = value
+			continue
+		}
+		if value, err := time.ParseDuration(field); err == nil && value > 0 {
+			budget = value
+			continue
+		}
+		return 0, 0, fmt.Errorf("bad setting %q in %q (want a %s or a time budget like 20ms)", field, name, count)
+	}
+
+	return n, budget, nil
+}
+
+// Factory returns a function that creates fresh instances of the named algorithm
+func Factory(name string) (func() game.Algorithm, error) {
+	if _, err := New(name); err != nil {
+		return nil, err
+	}
+
+	return func() game.Algorithm {
+		algorithm, _ := New(name)
+		return algorithm
+	}, nil
+}
+
+// Factories returns a factory for each of the named algorithms
+func Factories(names []string) ([]func() game.Algorithm, error) {
+	factories := make([]func() game.Algorithm, len(names))
+	for i, name := range names {
+		factory, err := Factory(name)
+		if err != nil {
+			return nil, err
+		}
+		factories[i] = factory
+	}
+	return factories, nil
+}
+
+// Reproducible reports whether an algorithm always decides the same way from
+// the same observations, so a logged game it played can be replayed exactly.
+// A search with a time budget does as much searching as the machine allows.
+func Reproducible(algorithm game.Algorithm) bool {
+	switch algorithm := algorithm.(type) {
+	case *MonteCarloAlgorithm:
+		return algorithm.config.Budget == 0
+	case *ISMCTSAlgorithm:
+		return algorithm.config.Budget == 0
+	}
+	return true
+}
+
+// SameName reports whether two algorithm names match, ignoring case, spaces, hyphens and underscores
+func SameName(a, b string) bool {
+	return normalizeName(a) == normalizeName(b)
+}
+
+// normalizeName lowercases a name and strips spaces, hyphens and underscores
+func normalizeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' || r == '_' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(name))
+}
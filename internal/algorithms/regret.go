@@ -0,0 +1,112 @@
+package algorithms
+
+import (
+	"flip7-simulator/internal/game"
+	"sync"
+)
+
+// Regret is how much expected round score an algorithm gave up against
+// optimal single-round play over its decisions
+type Regret struct {
+	Decisions int
+	Mistakes  int     // Decisions that gave up any expected score
+	Total     float64 // Expected round score given up over all decisions
+}
+
+// PerDecision returns the average expected round score given up per decision
+func (r Regret) PerDecision() float64 {
+	if r.Decisions == 0 {
+		return 0
+	}
+	return r.Total / float64(r.Decisions)
+}
+
+// MistakeRate returns the fraction of decisions that gave up expected score
+func (r Regret) MistakeRate() float64 {
+	if r.Decisions == 0 {
+		return 0
+	}
+	return float64(r.Mistakes) / float64(r.Decisions)
+}
+
+// RegretTally scores every decision of the algorithms it wraps against
+// RoundValues, keyed by algorithm name. It is safe for concurrent use, so one
+// tally can be shared by every worker of a run.
+type RegretTally struct {
+	mu      sync.Mutex
+	regrets map[string]*Regret
+}
+
+func NewRegretTally() *RegretTally {
+	return &RegretTally{
+		regrets: make(map[string]*Regret),
+	}
+}
+
+// Wrap returns an algorithm that plays exactly like algorithm while recording its regret
+func (t *RegretTally) Wrap(algorithm game.Algorithm) game.Algorithm {
+	return &regretAlgorithm{Algorithm: algorithm, tally: t}
+}
+
+// WrapFactories wraps every algorithm the factories create
+func (t *RegretTally) WrapFactories(factories []func() game.Algorithm) []func() game.Algorithm {
+	wrapped := make([]func() game.Algorithm, len(factories))
+	for i, factory := range factories {
+		factory := factory
+		wrapped[i] = func() game.Algorithm {
+			return t.Wrap(factory())
+		}
+	}
+	return wrapped
+}
+
+// Get returns the regret recorded for the named algorithm
+func (t *RegretTally) Get(name string) Regret {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if regret, ok := t.regrets[name]; ok {
+		return *regret
+	}
+	return Regret{}
+}
+
+// record adds one decision's regret for the named algorithm
+func (t *RegretTally) record(name string, regret float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	entry, ok := t.regrets[name]
+	if !ok {
+		entry = &Regret{}
+		t.regrets[name] = entry
+	}
+
+	entry.Decisions++
+	entry.Total += regret
+	if regret > 1e-9 {
+		entry.Mistakes++
+	}
+}
+
+// regretAlgorithm passes decisions through to the wrapped algorithm, scoring each one
+type regretAlgorithm struct {
+	game.Algorithm
+	tally *RegretTally
+}
+
+func (a *regretAlgorithm) MakeDecision(obs game.Observation) game.Decision {
+	decision := a.Algorithm.MakeDecision(obs)
+
+	stand, hit, err := RoundValues(obs)
+	if err != nil {
+		return decision
+	}
+	best, chosen := max(stand, hit), stand
+	if decision.Action == "hit" {
+		chosen = hit
+	}
+	a.tally.record(a.GetName(), best-chosen)
+
+	return decision
+}
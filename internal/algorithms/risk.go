@@ -0,0 +1,37 @@
+package algorithms
+
+import (
+	"flip7-simulator/internal/game"
+)
+
+// bustChance is the shared bust-risk estimate for the rule-based strategies: the
+// chance the next card is a number the player already holds, from the number
+// cards remaining. It reports false if no number cards remain.
+//
+// Holding a Second Chance, a duplicate does not bust the hand. It costs the
+// Second Chance instead and leaves the next draw unprotected, which is worth
+// some but not all of a bust. How much depends on how much risk a strategy is
+// willing to carry, so each passes its own secondChanceCost: the share of a
+// bust a duplicate counts as while it holds a Second Chance, from 0 (free) to
+// 1 (as bad as busting).
+func bustChance(playerState game.PlayerState, cardsRemaining map[int]int, secondChanceCost float64) (float64, bool) {
+	uniqueValues := playerState.UniqueValues()
+
+	duplicates, totalCards := 0, 0
+	for value, count := range cardsRemaining {
+		totalCards += count
+		if uniqueValues[value] {
+			duplicates += count
+		}
+	}
+
+	if totalCards == 0 {
+		return 0, false
+	}
+
+	risk := float64(duplicates) / float64(totalCards)
+	if playerState.HasSecondChance() {
+		risk *= secondChanceCost
+	}
+	return risk, true
+}
@@ -46,6 +46,10 @@ func (a *StopAtScoreAlgorithm) MakeDecision(playerState game.PlayerState, gameSt
 	return game.Decision{Action: "hit"}
 }
 
+func (a *StopAtScoreAlgorithm) ChooseTarget(playerState game.PlayerState, gameState game.GameState, card game.Card, candidates []int) int {
+	return chooseDefaultTarget(playerState, gameState, card, candidates)
+}
+
 func (a *StopAtScoreAlgorithm) GetName() string {
 	return a.name
 }
@@ -0,0 +1,85 @@
+package algorithms
+
+import (
+	"flip7-simulator/internal/game"
+)
+
+// chooseDefaultTarget is the shared targeting heuristic for action cards.
+// A Freeze goes to the active opponent with the weakest hand, so they bank as
+// little as possible. A Flip Three goes to the active opponent holding the most
+// unique numbers, who is the most likely to bust. A spare Second Chance is given
+// to the opponent with the lowest game score. If there is no opponent to
+// target, the player targets themselves.
+func chooseDefaultTarget(playerState game.PlayerState, gameState game.GameState, card game.Card, candidates []int) int {
+	switch card.Action {
+	case "flip_three":
+		return mostUniqueOpponent(playerState, gameState, candidates)
+	case "second_chance":
+		return trailingOpponent(playerState, gameState, candidates)
+	}
+
+	target := playerState.ID
+	bestHandScore := -1
+
+	for _, candidate := range candidates {
+		if candidate == playerState.ID {
+			continue
+		}
+
+		handScore := 0
+		for _, c := range gameState.Players[candidate].Cards {
+			if c.CardType == "number" {
+				handScore += c.Value
+			} else if c.CardType == "modifier" {
+				handScore += c.Modifier
+			}
+		}
+
+		if bestHandScore == -1 || handScore < bestHandScore {
+			bestHandScore = handScore
+			target = candidate
+		}
+	}
+
+	return target
+}
+
+// mostUniqueOpponent returns the candidate opponent holding the most unique number values
+func mostUniqueOpponent(playerState game.PlayerState, gameState game.GameState, candidates []int) int {
+	target := playerState.ID
+	mostUnique := -1
+
+	for _, candidate := range candidates {
+		if candidate == playerState.ID {
+			continue
+		}
+
+		uniqueCount := len(gameState.Players[candidate].UniqueValues())
+		if uniqueCount > mostUnique {
+			mostUnique = uniqueCount
+			target = candidate
+		}
+	}
+
+	return target
+}
+
+// trailingOpponent returns the candidate opponent with the lowest game score
+func trailingOpponent(playerState game.PlayerState, gameState game.GameState, candidates []int) int {
+	target := candidates[0]
+	lowestScore := -1
+
+	for _, candidate := range candidates {
+		if candidate == playerState.ID {
+			continue
+		}
+
+		score := gameState.Players[candidate].GameScore
+		if lowestScore == -1 || score < lowestScore {
+			lowestScore = score
+			target = candidate
+		}
+	}
+
+	return target
+}
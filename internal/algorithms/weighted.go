@@ -0,0 +1,147 @@
+package algorithms
+
+import (
+	"encoding/json"
+	"flip7-simulator/internal/game"
+	"fmt"
+	"os"
+)
+
+// GenomeFeatures names what a WeightedAlgorithm weighs, in the order of a genome's weights:
+//   - bias: always 1
+//   - bust_risk: chance the next card busts the hand, 0 while holding a Second Chance
+//   - round_score: the hand's round score, in fifties
+//   - unique_count: number values held, in sevenths
+//   - score_gap: how far the leading opponent is ahead of the player, as a fraction of the target
+//   - cards_left: unseen cards as a fraction of the deck
+var GenomeFeatures = []string{"bias", "bust_risk", "round_score", "unique_count", "score_gap", "cards_left"}
+
+// Genome configures a WeightedAlgorithm: a weight for each of GenomeFeatures
+type Genome struct {
+	Name     string    `json:"name"`
+	Features []string  `json:"features"`
+	Weights  []float64 `json:"weights"`           // By feature
+	Fitness  float64   `json:"fitness,omitempty"` // Win rate the genome was selected on, if it was evolved
+}
+
+// LoadGenome reads a genome written by Save
+func LoadGenome(path string) (*Genome, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var genome Genome
+	if err := json.Unmarshal(data, &genome); err != nil {
+		return nil, fmt.Errorf("reading genome from %s: %w", path, err)
+	}
+	if err := genome.validate(); err != nil {
+		return nil, fmt.Errorf("reading genome from %s: %w", path, err)
+	}
+
+	return &genome, nil
+}
+
+// Save writes the genome to a JSON file
+func (g *Genome) Save(path string) error {
+	data, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// validate checks the genome weighs the features a WeightedAlgorithm computes
+func (g *Genome) validate() error {
+	if g.Name == "" {
+		return fmt.Errorf("genome has no name")
+	}
+	if len(g.Features) != len(GenomeFeatures) || len(g.Weights) != len(GenomeFeatures) {
+		return fmt.Errorf("genome has %d features and %d weights, want %d", len(g.Features), len(g.Weights), len(GenomeFeatures))
+	}
+	for i, feature := range g.Features {
+		if feature != GenomeFeatures[i] {
+			return fmt.Errorf("genome feature %d is %q, want %q", i, feature, GenomeFeatures[i])
+		}
+	}
+	return nil
+}
+
+// WeightedAlgorithm hits while the weighted sum of its features is positive.
+// It is the strategy the evolve subcommand searches over.
+type WeightedAlgorithm struct {
+	name    string
+	weights []float64
+}
+
+// NewWeightedAlgorithm creates a weighted algorithm from a genome
+func NewWeightedAlgorithm(genome *Genome) *WeightedAlgorithm {
+	return &WeightedAlgorithm{
+		name:    genome.Name,
+		weights: append([]float64(nil), genome.Weights...),
+	}
+}
+
+func (a *WeightedAlgorithm) MakeDecision(obs game.Observation) game.Decision {
+	sum := 0.0
+	for i, feature := range genomeFeatures(obs) {
+		sum += a.weights[i] * feature
+	}
+
+	if sum > 0 {
+		return game.Decision{Action: "hit"}
+	}
+	return game.Decision{Action: "stand"}
+}
+
+func (a *WeightedAlgorithm) ChooseTarget(obs game.Observation, card game.Card, candidates []int) int {
+	return chooseDefaultTarget(obs.Self(), game.GameState{Players: obs.Players()}, card, candidates)
+}
+
+func (a *WeightedAlgorithm) GetName() string {
+	return a.name
+}
+
+// genomeFeatures computes each of GenomeFeatures for the observing player
+func genomeFeatures(obs game.Observation) []float64 {
+	self := obs.Self()
+
+	held := self.UniqueValues()
+	unseen := obs.UnseenCount()
+	bustRisk := 0.0
+	if unseen > 0 && !self.HasSecondChance() {
+		busting := 0
+		for value, count := range obs.UnseenNumbers() {
+			if held[value] {
+				busting += count
+			}
+		}
+		bustRisk = float64(busting) / float64(unseen)
+	}
+
+	leaderTotal, faceUp := 0, len(obs.DiscardPile())
+	for _, player := range obs.Players() {
+		if player.ID != self.ID && player.GameScore > leaderTotal {
+			leaderTotal = player.GameScore
+		}
+		faceUp += len(player.Cards)
+	}
+	target := obs.Config().TargetScore
+	if target <= 0 {
+		target = game.DefaultGameConfig().TargetScore
+	}
+
+	cardsLeft := 0.0
+	if unseen+faceUp > 0 {
+		cardsLeft = float64(unseen) / float64(unseen+faceUp)
+	}
+
+	return []float64{
+		1,
+		bustRisk,
+		float64(self.RoundScore()) / 50,
+		float64(len(held)) / 7,
+		float64(leaderTotal-self.GameScore) / float64(target),
+		cardsLeft,
+	}
+}
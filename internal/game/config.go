@@ -0,0 +1,39 @@
+package game
+
+import "fmt"
+
+// GameConfig sets when a game ends
+type GameConfig struct {
+	TargetScore int `json:"target_score"` // The game ends after a round in which a player reaches this score
+	MaxRounds   int `json:"max_rounds"`   // The game ends after this many rounds even if nobody reached the target (0 = no limit)
+	FixedRounds int `json:"fixed_rounds"` // Play exactly this many rounds and the highest total wins, ignoring the target (0 = off)
+}
+
+// DefaultGameConfig returns the standard race to 200 points
+func DefaultGameConfig() GameConfig {
+	return GameConfig{TargetScore: 200}
+}
+
+// String describes the game length, e.g. "first to 200, max 30 rounds"
+func (c GameConfig) String() string {
+	if c.FixedRounds > 0 {
+		return fmt.Sprintf("%d rounds, highest total wins", c.FixedRounds)
+	}
+
+	description := fmt.Sprintf("first to %d", c.TargetScore)
+	if c.MaxRounds > 0 {
+		description += fmt.Sprintf(", max %d rounds", c.MaxRounds)
+	}
+	return description
+}
+
+// Validate reports settings that would stop a game from ever ending
+func (c GameConfig) Validate() error {
+	if c.FixedRounds < 0 || c.MaxRounds < 0 {
+		return fmt.Errorf("round counts cannot be negative")
+	}
+	if c.FixedRounds == 0 && c.TargetScore <= 0 {
+		return fmt.Errorf("target score must be positive unless a fixed number of rounds is played")
+	}
+	return nil
+}
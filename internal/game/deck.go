@@ -0,0 +1,145 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+)
+
+// DeckSpec describes how many of each card make up a deck
+type DeckSpec struct {
+	Name      string         `json:"name"`
+	Numbers   map[int]int    `json:"numbers"`   // Number value -> count
+	Modifiers map[int]int    `json:"modifiers"` // +N modifier -> count
+	X2        int            `json:"x2"`        // Number of x2 multiplier cards
+	Actions   map[string]int `json:"actions"`   // Action name -> count
+}
+
+// OfficialDeck returns the 94-card deck from the published game
+func OfficialDeck() DeckSpec {
+	return DeckSpec{
+		Name:      "official",
+		Numbers:   standardNumbers(),
+		Modifiers: map[int]int{2: 1, 4: 1, 6: 1, 8: 1, 10: 1},
+		X2:        1,
+		Actions:   standardActions(),
+	}
+}
+
+// LegacyDeck returns the deck used by earlier versions of the simulator,
+// which guessed at two each of +1, +2 and +3 modifiers and had no action cards
+func LegacyDeck() DeckSpec {
+	return DeckSpec{
+		Name:      "legacy",
+		Numbers:   standardNumbers(),
+		Modifiers: map[int]int{1: 2, 2: 2, 3: 2},
+		X2:        1,
+	}
+}
+
+// DeckSpecByName returns the preset deck with the given name
+func DeckSpecByName(name string) (DeckSpec, error) {
+	switch name {
+	case "official":
+		return OfficialDeck(), nil
+	case "legacy":
+		return LegacyDeck(), nil
+	}
+	return DeckSpec{}, fmt.Errorf("unknown deck %q (want official or legacy)", name)
+}
+
+// standardNumbers is twelve 12-value cards down to two 2-value cards, plus a single 1 and 0
+func standardNumbers() map[int]int {
+	numbers := make(map[int]int)
+	for value := 0; value <= 12; value++ {
+		count := 1
+		if value >= 2 {
+			count = value
+		}
+		numbers[value] = count
+	}
+	return numbers
+}
+
+// standardActions is 3 each of Freeze, Flip Three and Second Chance
+func standardActions() map[string]int {
+	return map[string]int{
+		"freeze":        3,
+		"flip_three":    3,
+		"second_chance": 3,
+	}
+}
+
+// Cards returns every card in the spec, unshuffled and in a fixed order
+func (d DeckSpec) Cards() []Card {
+	cards := make([]Card, 0, d.Size())
+
+	for _, value := range sortedKeys(d.Numbers) {
+		for i := 0; i < d.Numbers[value]; i++ {
+			cards = append(cards, Card{Value: value, CardType: "number"})
+		}
+	}
+
+	for _, mod := range sortedKeys(d.Modifiers) {
+		for i := 0; i < d.Modifiers[mod]; i++ {
+			cards = append(cards, Card{CardType: "modifier", Modifier: mod})
+		}
+	}
+
+	for i := 0; i < d.X2; i++ {
+		cards = append(cards, Card{CardType: "modifier", IsX2: true})
+	}
+
+	actions := make([]string, 0, len(d.Actions))
+	for action := range d.Actions {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+
+	for _, action := range actions {
+		for i := 0; i < d.Actions[action]; i++ {
+			cards = append(cards, Card{CardType: "action", Action: action})
+		}
+	}
+
+	return cards
+}
+
+// kinds returns the distinct cards in the spec, in Cards order, with how many of each it holds
+func (d DeckSpec) kinds() ([]Card, []int) {
+	kinds := make([]Card, 0)
+	counts := make([]int, 0)
+	for _, card := range d.Cards() {
+		if len(kinds) > 0 && kinds[len(kinds)-1] == card {
+			counts[len(counts)-1]++
+			continue
+		}
+		kinds = append(kinds, card)
+		counts = append(counts, 1)
+	}
+	return kinds, counts
+}
+
+// Size returns the total number of cards in the spec
+func (d DeckSpec) Size() int {
+	size := d.X2
+	for _, count := range d.Numbers {
+		size += count
+	}
+	for _, count := range d.Modifiers {
+		size += count
+	}
+	for _, count := range d.Actions {
+		size += count
+	}
+	return size
+}
+
+// sortedKeys returns the keys of an int-keyed map in ascending order
+func sortedKeys(m map[int]int) []int {
+	keys := make([]int, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+	return keys
+}
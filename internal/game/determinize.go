@@ -0,0 +1,141 @@
+package game
+
+import "math/rand"
+
+// Clone returns a copy of the game that shares no state with it, for playing
+// out possible futures. The copy plays with the same algorithms but logs no
+// events, and any reshuffle within the current round is seeded afresh rather
+// than continuing the original's shuffle.
+func (g *Game) Clone() *Game {
+	clone := *g
+
+	clone.Players = make([]PlayerState, len(g.Players))
+	for i, player := range g.Players {
+		clone.Players[i] = copyPlayer(player)
+	}
+	clone.Deck = append([]Card(nil), g.Deck...)
+	clone.DiscardPile = append([]Card(nil), g.DiscardPile...)
+	clone.Algorithms = append([]Algorithm(nil), g.Algorithms...)
+	clone.Winners = append([]int(nil), g.Winners...)
+	clone.pending = append([]pendingAction(nil), g.pending...)
+	clone.progress = g.progress.copy()
+	clone.Events = nil
+	clone.rng = rand.New(g.source(roundSeed(g.Seed, g.CurrentRound)))
+
+	return &clone
+}
+
+// Determinize builds a game consistent with everything the observation shows,
+// with the unseen cards dealt into the deck in a random order and a random
+// seed for later rounds. It is one possible version of the hidden state, for
+// algorithms that search by sampling; Algorithms are left for the caller to
+// set. Play is left waiting on the decision the observation was made for.
+// The deck spec is rebuilt from the observation's card counts, and later
+// rounds are played with a fresh deck each and a shared-win tie-breaker. Its
+// shuffles use a cheaper random source than a dealt game's.
+func (o Observation) Determinize(rng *rand.Rand) *Game {
+	g := &Game{
+		Players:      make([]PlayerState, len(o.players)),
+		DiscardPile:  append([]Card(nil), o.discardPile...),
+		Dealer:       o.dealer,
+		CurrentRound: o.round,
+		Config:       o.config,
+		Winner:       -1,
+		Seed:         rng.Int63(),
+		pending:      append([]pendingAction(nil), o.pending...),
+		progress:     o.progress.copy(),
+		newSource:    newSearchSource,
+	}
+	g.rng = rand.New(g.source(g.Seed))
+
+	// Every card is either unseen, in front of a player, in the discard pile or waiting to be played
+	counts := append([]int(nil), o.unseen...)
+	seen := func(card Card) {
+		for i, kind := range o.kinds {
+			if kind == card {
+				counts[i]++
+				return
+			}
+		}
+	}
+	for i, player := range o.players {
+		g.Players[i] = copyPlayer(player)
+		for _, card := range player.Cards {
+			seen(card)
+		}
+	}
+	for _, card := range o.discardPile {
+		seen(card)
+	}
+	for _, action := range o.pending {
+		seen(action.card)
+	}
+	if flip := o.progress.flip; flip != nil {
+		for _, action := range flip.queued {
+			seen(action.card)
+		}
+	}
+	if choice := o.progress.choice; choice != nil && choice.card != nil {
+		seen(*choice.card)
+	}
+	g.Spec = specFromCounts(o.kinds, counts)
+	g.cardKinds, g.kindCounts = o.kinds, counts
+
+	for i, kind := range o.kinds {
+		for n := 0; n < o.unseen[i]; n++ {
+			g.Deck = append(g.Deck, kind)
+		}
+	}
+	g.ShuffleDeck()
+
+	return g
+}
+
+// specFromCounts builds a deck spec holding the given number of each card
+func specFromCounts(kinds []Card, counts []int) DeckSpec {
+	spec := DeckSpec{
+		Numbers:   make(map[int]int),
+		Modifiers: make(map[int]int),
+		Actions:   make(map[string]int),
+	}
+	for i, card := range kinds {
+		switch {
+		case card.CardType == "number":
+			spec.Numbers[card.Value] += counts[i]
+		case card.CardType == "modifier" && card.IsX2:
+			spec.X2 += counts[i]
+		case card.CardType == "modifier":
+			spec.Modifiers[card.Modifier] += counts[i]
+		case card.CardType == "action":
+			spec.Actions[card.Action] += counts[i]
+		}
+	}
+	return spec
+}
+
+// searchSource is a splitmix64 random source. Games played out by a search
+// reshuffle every round, and seeding rand.NewSource would cost more than
+// playing the round.
+type searchSource struct {
+	state uint64
+}
+
+func newSearchSource(seed int64) rand.Source {
+	return &searchSource{state: uint64(seed)}
+}
+
+func (s *searchSource) Seed(seed int64) {
+	s.state = uint64(seed)
+}
+
+func (s *searchSource) Int63() int64 {
+	return int64(s.Uint64() >> 1)
+}
+
+func (s *searchSource) Uint64() uint64 {
+	s.state += 0x9E3779B97F4A7C15
+	z := s.state
+	z = (z ^ (z >> 30)) * 0xBF58476D1CE4E5B9
+	z = (z ^ (z >> 27)) * 0x94D049BB133111EB
+	return z ^ (z >> 31)
+}
@@ -0,0 +1,168 @@
+package game
+
+import "encoding/json"
+
+// Event is one step of a game. Type is one of "game_start", "round_start",
+// "card_dealt", "hit", "stand", "action", "second_chance", "bust", "flip7",
+// "round_scored" or "game_over"; only the fields that apply to it are set.
+type Event struct {
+	Game        int          `json:"game"` // Game number within a run, set by whoever logs the event
+	Type        string       `json:"type"`
+	Round       int          `json:"round"`
+	Player      *int         `json:"player,omitempty"` // Player the event happened to, or who played the action
+	Card        *Card        `json:"card,omitempty"`
+	Target      *int         `json:"target,omitempty"`      // Player an action card was played on
+	Candidates  []int        `json:"candidates,omitempty"`  // Players the action card could have been played on
+	Reason      string       `json:"reason,omitempty"`      // Why a player stood without choosing to, e.g. "deck_empty"
+	Observation *Observation `json:"observation,omitempty"` // What the deciding algorithm saw
+	Dealer      *int         `json:"dealer,omitempty"`
+	Scores      []int        `json:"scores,omitempty"`      // Round scores by player
+	GameScores  []int        `json:"game_scores,omitempty"` // Game totals by player
+	Hands       [][]Card     `json:"hands,omitempty"`       // Cards in front of each player when the round was scored
+	Winners     []int        `json:"winners,omitempty"`
+	Seed        int64        `json:"seed,omitempty"`
+	Algorithms  []string     `json:"algorithms,omitempty"` // Algorithm names by player
+	Deck        *DeckSpec    `json:"deck,omitempty"`
+	Config      *GameConfig  `json:"config,omitempty"`
+	TieBreaker  string       `json:"tie_breaker,omitempty"`
+	Persistent  bool         `json:"persistent_deck,omitempty"`
+}
+
+// EventSink receives a game's events as they happen
+type EventSink interface {
+	Emit(event Event)
+}
+
+// EventRecorder is an EventSink that keeps every event in memory
+type EventRecorder struct {
+	Events []Event
+}
+
+// Emit records the event
+func (r *EventRecorder) Emit(event Event) {
+	r.Events = append(r.Events, event)
+}
+
+// StartEvent returns the game_start event describing the game's seed, seating
+// and settings. Callers emit it once the game is set up, before the first round.
+func (g *Game) StartEvent(persistentDeck bool) Event {
+	names := make([]string, len(g.Algorithms))
+	for i, algorithm := range g.Algorithms {
+		if algorithm != nil {
+			names[i] = algorithm.GetName()
+		}
+	}
+
+	tieBreaker := g.TieBreaker
+	if tieBreaker == nil {
+		tieBreaker = SharedWinTieBreaker{}
+	}
+
+	spec, config := g.Spec, g.Config
+	return Event{
+		Type:       "game_start",
+		Dealer:     intRef(g.Dealer),
+		Seed:       g.Seed,
+		Algorithms: names,
+		Deck:       &spec,
+		Config:     &config,
+		TieBreaker: tieBreaker.GetName(),
+		Persistent: persistentDeck,
+	}
+}
+
+// Emit sends an event to the game's event sink, stamping it with the current round
+func (g *Game) Emit(event Event) {
+	if g.Events == nil {
+		return
+	}
+	event.Round = g.CurrentRound
+	g.Events.Emit(event)
+}
+
+// emitPlayer emits an event that happened to a player
+func (g *Game) emitPlayer(eventType string, playerID int, card *Card) {
+	if g.Events == nil {
+		return
+	}
+	g.Emit(Event{Type: eventType, Player: intRef(playerID), Card: card})
+}
+
+// playerScores returns each player's round score and game total
+func (g *Game) playerScores() ([]int, []int) {
+	scores := make([]int, len(g.Players))
+	gameScores := make([]int, len(g.Players))
+	for i, player := range g.Players {
+		scores[i] = g.CalculateScore(i)
+		gameScores[i] = player.GameScore
+	}
+	return scores, gameScores
+}
+
+// intRef returns a pointer to a copy of an int, for optional event fields
+func intRef(value int) *int {
+	return &value
+}
+
+// observationJSON is the serialized form of an Observation
+type observationJSON struct {
+	PlayerID    int           `json:"player_id"`
+	Players     []PlayerState `json:"players"`
+	DiscardPile []Card        `json:"discard_pile"`
+	Unseen      []unseenCard  `json:"unseen"`
+	TurnOrder   []int         `json:"turn_order"`
+	Dealer      int           `json:"dealer"`
+	Round       int           `json:"round"`
+	Config      GameConfig    `json:"config"`
+}
+
+// unseenCard is how many of a card are unseen, in a serialized Observation
+type unseenCard struct {
+	Card  Card `json:"card"`
+	Count int  `json:"count"`
+}
+
+// MarshalJSON writes everything the observing player could see
+func (o Observation) MarshalJSON() ([]byte, error) {
+	unseen := make([]unseenCard, len(o.kinds))
+	for i, kind := range o.kinds {
+		unseen[i] = unseenCard{Card: kind, Count: o.unseen[i]}
+	}
+
+	return json.Marshal(observationJSON{
+		PlayerID:    o.playerID,
+		Players:     o.players,
+		DiscardPile: o.discardPile,
+		Unseen:      unseen,
+		TurnOrder:   o.turnOrder,
+		Dealer:      o.dealer,
+		Round:       o.round,
+		Config:      o.config,
+	})
+}
+
+// UnmarshalJSON rebuilds a logged observation, so an algorithm can be shown exactly what it saw
+func (o *Observation) UnmarshalJSON(data []byte) error {
+	var decoded observationJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	*o = Observation{
+		playerID:    decoded.PlayerID,
+		players:     decoded.Players,
+		discardPile: decoded.DiscardPile,
+		kinds:       make([]Card, len(decoded.Unseen)),
+		unseen:      make([]int, len(decoded.Unseen)),
+		turnOrder:   decoded.TurnOrder,
+		dealer:      decoded.Dealer,
+		round:       decoded.Round,
+		config:      decoded.Config,
+	}
+	for i, card := range decoded.Unseen {
+		o.kinds[i] = card.Card
+		o.unseen[i] = card.Count
+	}
+
+	return nil
+}
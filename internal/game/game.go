@@ -2,26 +2,47 @@ package game
 
 import (
 	"fmt"
+	"io"
 	"math/rand"
+	"os"
+	"sort"
 	"time"
 )
 
 // Card represents a card in the Flip 7 deck
 type Card struct {
-	Value    int    // 0-12 for number cards
-	CardType string // "number", "modifier", "action"
-	Modifier int    // For +1, +2, +3 cards
-	IsX2     bool   // For x2 multiplier card
+	Value    int    `json:"value"`              // 0-12 for number cards
+	CardType string `json:"type"`               // "number", "modifier", "action"
+	Modifier int    `json:"modifier,omitempty"` // For +N modifier cards
+	IsX2     bool   `json:"x2,omitempty"`       // For x2 multiplier card
+	Action   string `json:"action,omitempty"`   // For action cards: "freeze", "flip_three", "second_chance"
+}
+
+// String shows a card the way it is printed in game states: "7", "+4", "x2" or "[freeze]"
+func (c Card) String() string {
+	switch c.CardType {
+	case "modifier":
+		if c.IsX2 {
+			return "x2"
+		}
+		return fmt.Sprintf("+%d", c.Modifier)
+	case "action":
+		return fmt.Sprintf("[%s]", c.Action)
+	default:
+		return fmt.Sprintf("%d", c.Value)
+	}
 }
 
 // PlayerState represents the current state of a player
 type PlayerState struct {
-	ID        int
-	Cards     []Card
-	Score     int
-	IsBust    bool
-	HasStood  bool
-	GameScore int // Total score across all games
+	ID         int    `json:"id"`
+	Cards      []Card `json:"cards"`
+	Score      int    `json:"score"`
+	IsBust     bool   `json:"is_bust"`
+	HasStood   bool   `json:"has_stood"`
+	GameScore  int    `json:"game_score"`  // Total score across all games
+	Flip7s     int    `json:"flip7s"`      // Flip 7s achieved this game
+	MaxDeficit int    `json:"max_deficit"` // Largest gap behind the leader at the end of any round
 }
 
 // GameState represents the current state of the game
@@ -30,8 +51,10 @@ type GameState struct {
 	Deck         []Card
 	DiscardPile  []Card
 	CurrentRound int
+	Dealer       int
 	IsGameOver   bool
-	Winner       int
+	Winner       int   // First winning player ID, or -1 while the game is in progress
+	Winners      []int // All winning player IDs; more than one for a shared win
 }
 
 // Decision represents a player's choice
@@ -39,25 +62,64 @@ type Decision struct {
 	Action string // "hit" or "stand"
 }
 
-// Algorithm interface for different playing strategies
+// Algorithm interface for different playing strategies. Algorithms only see
+// public information through an Observation; see FromLegacy for algorithms
+// written against the original GameState interface.
 type Algorithm interface {
-	MakeDecision(playerState PlayerState, gameState GameState, cardsRemaining map[int]int) Decision
+	MakeDecision(obs Observation) Decision
+	// ChooseTarget picks which of the candidate players an action card is played on
+	ChooseTarget(obs Observation, card Card, candidates []int) int
 	GetName() string
 }
 
 // Game manages the Flip 7 game logic
 type Game struct {
-	Players     []PlayerState
-	Deck        []Card
-	DiscardPile []Card
-	rng         *rand.Rand
+	Players      []PlayerState
+	Deck         []Card
+	DiscardPile  []Card
+	Spec         DeckSpec
+	Algorithms   []Algorithm // Indexed by player ID; required by PlayRound and used to choose action card targets
+	Dealer       int         // Player ID of the dealer; play starts to their left
+	CurrentRound int
+	Config       GameConfig
+	TieBreaker   TieBreaker // Settles ties for the highest score at game end; defaults to a shared win
+	TiedFinish   bool       // Set when the game reached its end with several players level on the highest score
+	IsGameOver   bool
+	Winner       int
+	Winners      []int
+	Seed         int64     // Seed for every shuffle in the game
+	Events       EventSink // Receives the game's events as they happen; nil logs nothing
+	rng          *rand.Rand
+	newSource    func(seed int64) rand.Source // Source for each round's shuffles; nil means rand.NewSource
+	pending      []pendingAction
+	progress     roundProgress // Where play has got to in the current round
+	specCards    []Card        // Every card in Spec, cached for CreateDeck
+	cardKinds    []Card        // Distinct cards in Spec, cached for Observe
+	kindCounts   []int         // How many of each card in cardKinds Spec holds
+}
+
+// pendingAction is an action card waiting to be resolved by the player who revealed it
+type pendingAction struct {
+	playerID int
+	card     Card
 }
 
-// NewGame creates a new Flip 7 game
-func NewGame(numPlayers int) *Game {
+// NewGame creates a new Flip 7 game played with the given deck, shuffled from a clock-based seed
+func NewGame(numPlayers int, spec DeckSpec) *Game {
+	return NewSeededGame(numPlayers, spec, time.Now().UnixNano())
+}
+
+// NewSeededGame creates a new Flip 7 game whose shuffles are all driven by the
+// given seed, so the same seed and the same decisions replay the same game
+func NewSeededGame(numPlayers int, spec DeckSpec, seed int64) *Game {
 	game := &Game{
 		Players: make([]PlayerState, numPlayers),
-		rng:     rand.New(rand.NewSource(time.Now().UnixNano())),
+		Spec:    spec,
+		Config:  DefaultGameConfig(),
+		Dealer:  numPlayers - 1, // Player 0 acts first in the opening round
+		Winner:  -1,
+		Seed:    seed,
+		rng:     rand.New(rand.NewSource(seed)),
 	}
 
 	// Initialize players
@@ -71,47 +133,31 @@ func NewGame(numPlayers int) *Game {
 	return game
 }
 
-// CreateDeck creates the Flip 7 deck according to the rules
+// CreateDeck builds a fresh deck from the game's deck spec and shuffles it,
+// emptying the discard pile. The shuffle is seeded from the game seed and the
+// round number, so a round's deal is the same whatever happened in earlier rounds.
 func (g *Game) CreateDeck() {
-	g.Deck = make([]Card, 0)
-
-	// Number cards: 12 twelve-value cards down to 2 two-value cards, 1 one-value, 1 zero-value
-	for value := 0; value <= 12; value++ {
-		count := 1
-		if value >= 2 {
-			count = value
-		}
-
-		for i := 0; i < count; i++ {
-			g.Deck = append(g.Deck, Card{
-				Value:    value,
-				CardType: "number",
-			})
-		}
+	// The spec's cards only need listing once per game
+	if g.specCards == nil {
+		g.specCards = g.Spec.Cards()
 	}
+	g.Deck = append(make([]Card, 0, len(g.specCards)), g.specCards...)
+	g.DiscardPile = make([]Card, 0)
+	g.rng = rand.New(g.source(roundSeed(g.Seed, g.CurrentRound)))
+	g.ShuffleDeck()
+}
 
-	// Modifier cards (+1, +2, +3, x2)
-	// Adding some modifier cards (exact counts not specified in rules)
-	modifiers := []int{1, 2, 3}
-	for _, mod := range modifiers {
-		for i := 0; i < 2; i++ { // 2 of each modifier
-			g.Deck = append(g.Deck, Card{
-				Value:    0,
-				CardType: "modifier",
-				Modifier: mod,
-			})
-		}
+// source creates the random source for a round's shuffles
+func (g *Game) source(seed int64) rand.Source {
+	if g.newSource != nil {
+		return g.newSource(seed)
 	}
+	return rand.NewSource(seed)
+}
 
-	// Add x2 multiplier card
-	g.Deck = append(g.Deck, Card{
-		Value:    0,
-		CardType: "modifier",
-		IsX2:     true,
-	})
-
-	// Shuffle the deck
-	g.ShuffleDeck()
+// roundSeed mixes a round number into the game seed
+func roundSeed(seed int64, round int) int64 {
+	return int64(uint64(seed) ^ uint64(round)*0x9E3779B97F4A7C15)
 }
 
 // ShuffleDeck shuffles the current deck
@@ -122,14 +168,35 @@ func (g *Game) ShuffleDeck() {
 	}
 }
 
-// DealInitialCard deals one card to each player to start a round
+// TurnOrder returns player IDs in the order they are dealt to and act,
+// starting with the player to the dealer's left
+func (g *Game) TurnOrder() []int {
+	order := make([]int, len(g.Players))
+	for i := range order {
+		order[i] = (g.Dealer + 1 + i) % len(g.Players)
+	}
+	return order
+}
+
+// DealInitialCard deals one card to each player to start a round, asking the
+// algorithms for the targets of any action cards dealt
 func (g *Game) DealInitialCard() {
-	for i := range g.Players {
-		if !g.Players[i].HasStood && !g.Players[i].IsBust {
-			card := g.DrawCard()
-			if card != nil {
-				g.Players[i].Cards = append(g.Players[i].Cards, *card)
-			}
+	g.progress = roundProgress{}
+	for g.progress.dealt < len(g.Players) && !g.IsRoundOver() {
+		g.dealNext()
+		g.settle()
+	}
+}
+
+// dealNext deals the opening card to the next player in turn order, unless
+// they are already out of the round
+func (g *Game) dealNext() {
+	playerID := g.TurnOrder()[g.progress.dealt]
+	g.progress.dealt++
+
+	if g.isActive(playerID) {
+		if card := g.DrawCard(); card != nil {
+			g.receiveCard(playerID, *card)
 		}
 	}
 }
@@ -149,15 +216,184 @@ func (g *Game) DrawCard() *Card {
 	return &card
 }
 
-// ReshuffleDeck reshuffles the discard pile back into the deck
+// ReshuffleDeck reshuffles the discard pile back into the deck.
+// Cards still in front of players are not part of the reshuffle.
 func (g *Game) ReshuffleDeck() {
 	g.Deck = append(g.Deck, g.DiscardPile...)
 	g.DiscardPile = make([]Card, 0)
 	g.ShuffleDeck()
 }
 
-// PlayerHit gives a player another card
+// PlayRound deals the opening cards, then asks each active player's algorithm to
+// hit or stand in turn order until the round is over
+func (g *Game) PlayRound() {
+	g.BeginRound()
+	g.FinishRound()
+}
+
+// FinishRound plays the current round out from wherever it has got to, asking
+// the algorithms for every decision
+func (g *Game) FinishRound() {
+	for g.progress.choice != nil {
+		move, obs := g.ask()
+		g.playMove(move, obs)
+		g.advance()
+	}
+}
+
+// FinishGame plays the game out once the current round is over: it scores
+// the round, then plays rounds with a fresh deck each until the game is over
+func (g *Game) FinishGame() {
+	for {
+		g.ScoreRound()
+		if g.CheckGameOver() {
+			return
+		}
+
+		g.StartNewRound()
+		g.CreateDeck()
+		g.PlayRound()
+	}
+}
+
+// advance plays the round on until a player has a decision to make, dealing
+// the opening cards and then giving each active player a turn in turn order.
+// It reports whether play is waiting on a decision; false means the round is over.
+func (g *Game) advance() bool {
+	for !g.resolve() {
+		if g.IsRoundOver() {
+			return false
+		}
+
+		order := g.TurnOrder()
+		if g.progress.dealt < len(order) {
+			g.dealNext()
+			continue
+		}
+
+		// Play continues round the table, skipping anyone who has bust or stood
+		for !g.isActive(order[g.progress.turn%len(order)]) {
+			g.progress.turn++
+		}
+		g.progress.turn %= len(order)
+		g.progress.choice = &choice{playerID: order[g.progress.turn]}
+	}
+	return true
+}
+
+// settle resolves everything set off by the cards drawn so far, asking the
+// algorithms for any targets, without moving play on to the next player
+func (g *Game) settle() {
+	for g.resolve() {
+		move, obs := g.ask()
+		g.playMove(move, obs)
+	}
+}
+
+// resolve draws the rest of any Flip Three and resolves queued action cards in
+// order, stopping when a player has to choose a target. It reports whether
+// play is waiting on a decision. An action is discarded if the round has
+// ended or the player who revealed it is no longer active.
+func (g *Game) resolve() bool {
+	for g.progress.choice == nil {
+		switch {
+		case g.progress.flip != nil:
+			g.drawFlipThree()
+		case len(g.pending) > 0:
+			action := g.pending[0]
+			g.pending = g.pending[1:]
+
+			if g.IsRoundOver() || !g.isActive(action.playerID) {
+				g.DiscardPile = append(g.DiscardPile, action.card)
+				continue
+			}
+
+			g.resolveAction(action.playerID, action.card)
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// ask gets the decision play is waiting on from the deciding player's
+// algorithm, along with what they saw when making it. A target falls back to
+// the player themselves (or the first candidate) when the player has no
+// algorithm or makes no valid choice.
+func (g *Game) ask() (Move, *Observation) {
+	choice := g.progress.choice
+
+	if choice.card == nil {
+		obs := g.Observe(choice.playerID)
+		decision := g.Algorithms[choice.playerID].MakeDecision(obs)
+		if decision.Action == "hit" {
+			return Move{Action: "hit"}, &obs
+		}
+		return Move{Action: "stand"}, &obs
+	}
+
+	target := choice.candidates[0]
+	for _, candidate := range choice.candidates {
+		if candidate == choice.playerID {
+			target = choice.playerID
+		}
+	}
+
+	if choice.playerID >= len(g.Algorithms) || g.Algorithms[choice.playerID] == nil {
+		return Move{Action: "target", Target: target}, nil
+	}
+
+	obs := g.Observe(choice.playerID)
+	picked := g.Algorithms[choice.playerID].ChooseTarget(obs, *choice.card, append([]int(nil), choice.candidates...))
+	for _, candidate := range choice.candidates {
+		if candidate == picked {
+			target = picked
+		}
+	}
+	return Move{Action: "target", Target: target}, &obs
+}
+
+// playMove carries out the decision play is waiting on. The observation is
+// what the player decided on, for the event log.
+func (g *Game) playMove(move Move, obs *Observation) {
+	choice := g.progress.choice
+	g.progress.choice = nil
+
+	if choice.card != nil {
+		g.playAction(choice.playerID, *choice.card, choice.candidates, move.Target, obs)
+		return
+	}
+
+	g.progress.turn++
+	if move.Action == "hit" {
+		g.emitDecision("hit", choice.playerID, obs)
+
+		// With a persistent deck every card can be in play; a player who cannot draw has to stand
+		if !g.hit(choice.playerID) {
+			if g.Events != nil {
+				g.Emit(Event{Type: "stand", Player: intRef(choice.playerID), Reason: "deck_empty"})
+			}
+			g.PlayerStand(choice.playerID)
+		}
+	} else {
+		g.emitDecision("stand", choice.playerID, obs)
+		g.PlayerStand(choice.playerID)
+	}
+}
+
+// PlayerHit gives a player another card, asking the algorithms for the
+// targets of any action cards it sets off
 func (g *Game) PlayerHit(playerID int) bool {
+	if !g.hit(playerID) {
+		return false
+	}
+
+	g.settle()
+	return true
+}
+
+// hit deals a player another card, leaving any action cards it sets off to be resolved
+func (g *Game) hit(playerID int) bool {
 	if playerID < 0 || playerID >= len(g.Players) {
 		return false
 	}
@@ -172,26 +408,184 @@ func (g *Game) PlayerHit(playerID int) bool {
 		return false
 	}
 
+	g.receiveCard(playerID, *card)
+	return true
+}
+
+// receiveCard places a drawn card in front of a player, resolving busts and
+// Second Chances. Other action cards are queued and resolved afterwards by resolve.
+func (g *Game) receiveCard(playerID int, card Card) {
+	player := &g.Players[playerID]
+	g.emitPlayer("card_dealt", playerID, &card)
+
+	if card.CardType == "action" {
+		if card.Action == "second_chance" {
+			g.receiveSecondChance(playerID, card)
+			return
+		}
+		g.pending = append(g.pending, pendingAction{playerID: playerID, card: card})
+		return
+	}
+
 	// Check for bust condition (duplicate number value)
 	if card.CardType == "number" {
 		for _, existingCard := range player.Cards {
 			if existingCard.CardType == "number" && existingCard.Value == card.Value {
+				if player.HasSecondChance() {
+					// Discard the Second Chance together with the duplicate instead of busting
+					g.emitPlayer("second_chance", playerID, &card)
+					g.discardSecondChance(playerID)
+					g.DiscardPile = append(g.DiscardPile, card)
+					return
+				}
+
 				player.IsBust = true
+				g.emitPlayer("bust", playerID, &card)
 				g.DiscardPile = append(g.DiscardPile, player.Cards...)
+				g.DiscardPile = append(g.DiscardPile, card)
 				player.Cards = make([]Card, 0)
-				return true
+				return
 			}
 		}
 	}
 
-	player.Cards = append(player.Cards, *card)
+	player.Cards = append(player.Cards, card)
+	if card.CardType == "number" && g.HasFlip7(playerID) {
+		g.emitPlayer("flip7", playerID, nil)
+	}
+}
 
-	// Check for Flip 7
-	if g.HasFlip7(playerID) {
-		return true
+// receiveSecondChance keeps a Second Chance for the player. A player may only hold
+// one, so a second one must be given to another active player without one, or
+// discarded if nobody can take it.
+func (g *Game) receiveSecondChance(playerID int, card Card) {
+	if !g.Players[playerID].HasSecondChance() {
+		g.Players[playerID].Cards = append(g.Players[playerID].Cards, card)
+		return
 	}
 
-	return true
+	candidates := make([]int, 0, len(g.Players))
+	for _, id := range g.ActivePlayers() {
+		if id != playerID && !g.Players[id].HasSecondChance() {
+			candidates = append(candidates, id)
+		}
+	}
+
+	if len(candidates) == 0 {
+		g.DiscardPile = append(g.DiscardPile, card)
+		return
+	}
+
+	g.offerTarget(playerID, card, candidates)
+}
+
+// discardSecondChance moves a player's Second Chance to the discard pile
+func (g *Game) discardSecondChance(playerID int) {
+	player := &g.Players[playerID]
+	for i, card := range player.Cards {
+		if card.CardType == "action" && card.Action == "second_chance" {
+			g.DiscardPile = append(g.DiscardPile, card)
+			player.Cards = append(player.Cards[:i:i], player.Cards[i+1:]...)
+			return
+		}
+	}
+}
+
+// resolveAction has a player choose the target of an action card they drew
+func (g *Game) resolveAction(playerID int, card Card) {
+	candidates := g.ActivePlayers()
+	if len(candidates) == 0 {
+		g.DiscardPile = append(g.DiscardPile, card)
+		return
+	}
+
+	g.offerTarget(playerID, card, candidates)
+}
+
+// offerTarget has a player choose which of the candidates an action card is
+// played on. With a single candidate there is nothing to choose, so the card
+// is played at once; otherwise play waits on the player's choice.
+func (g *Game) offerTarget(playerID int, card Card, candidates []int) {
+	if len(candidates) == 1 {
+		g.playAction(playerID, card, candidates, candidates[0], nil)
+		return
+	}
+
+	g.progress.choice = &choice{playerID: playerID, card: &card, candidates: candidates}
+}
+
+// playAction plays an action card on the chosen target. The observation is
+// what the player chose on, if they were asked, for the event log.
+func (g *Game) playAction(playerID int, card Card, candidates []int, target int, seen *Observation) {
+	if g.Events != nil {
+		g.Emit(Event{
+			Type:        "action",
+			Player:      intRef(playerID),
+			Card:        &card,
+			Target:      intRef(target),
+			Candidates:  append([]int(nil), candidates...),
+			Observation: seen,
+		})
+	}
+
+	switch card.Action {
+	case "second_chance":
+		// Handed on by a player who already holds one
+		g.Players[target].Cards = append(g.Players[target].Cards, card)
+	case "freeze":
+		// The target banks their points and is out of the round
+		g.Players[target].Cards = append(g.Players[target].Cards, card)
+		g.PlayerStand(target)
+	case "flip_three":
+		// The target takes three cards in a row. Action cards revealed along
+		// the way are resolved after the sequence, ahead of anything already queued.
+		g.Players[target].Cards = append(g.Players[target].Cards, card)
+		g.progress.flip = &flipThree{target: target, left: 3, queued: g.pending}
+		g.pending = nil
+	default:
+		g.DiscardPile = append(g.DiscardPile, card)
+	}
+}
+
+// drawFlipThree draws the next card of a Flip Three, ending the sequence once
+// three are drawn, the target busts or the round ends
+func (g *Game) drawFlipThree() {
+	flip := g.progress.flip
+	if flip.left > 0 && !g.IsRoundOver() && g.isActive(flip.target) {
+		if card := g.DrawCard(); card != nil {
+			flip.left--
+			g.receiveCard(flip.target, *card)
+			return
+		}
+	}
+
+	g.pending = append(g.pending, flip.queued...)
+	g.progress.flip = nil
+}
+
+// emitDecision emits a hit or stand along with what the player saw when making it
+func (g *Game) emitDecision(decision string, playerID int, obs *Observation) {
+	if g.Events == nil {
+		return
+	}
+	g.Emit(Event{Type: decision, Player: intRef(playerID), Observation: obs})
+}
+
+// isActive reports whether a player has neither bust nor stood
+func (g *Game) isActive(playerID int) bool {
+	player := g.Players[playerID]
+	return !player.IsBust && !player.HasStood
+}
+
+// ActivePlayers returns the IDs of players who have neither bust nor stood
+func (g *Game) ActivePlayers() []int {
+	active := make([]int, 0, len(g.Players))
+	for _, player := range g.Players {
+		if !player.IsBust && !player.HasStood {
+			active = append(active, player.ID)
+		}
+	}
+	return active
 }
 
 // PlayerStand makes a player stand with their current cards
@@ -208,15 +602,35 @@ func (g *Game) HasFlip7(playerID int) bool {
 	}
 
 	player := g.Players[playerID]
+	if player.IsBust {
+		return false
+	}
+
+	return len(player.UniqueValues()) == 7
+}
+
+// UniqueValues returns the set of number values in front of the player.
+// Action and modifier cards never count towards Flip 7.
+func (p PlayerState) UniqueValues() map[int]bool {
 	uniqueValues := make(map[int]bool)
 
-	for _, card := range player.Cards {
+	for _, card := range p.Cards {
 		if card.CardType == "number" {
 			uniqueValues[card.Value] = true
 		}
 	}
 
-	return len(uniqueValues) == 7
+	return uniqueValues
+}
+
+// HasSecondChance reports whether the player is holding a Second Chance card
+func (p PlayerState) HasSecondChance() bool {
+	for _, card := range p.Cards {
+		if card.CardType == "action" && card.Action == "second_chance" {
+			return true
+		}
+	}
+	return false
 }
 
 // CalculateScore calculates a player's score for the round
@@ -225,8 +639,12 @@ func (g *Game) CalculateScore(playerID int) int {
 		return 0
 	}
 
-	player := g.Players[playerID]
-	if player.IsBust {
+	return g.Players[playerID].RoundScore()
+}
+
+// RoundScore scores the cards in front of the player this round
+func (p PlayerState) RoundScore() int {
+	if p.IsBust {
 		return 0
 	}
 
@@ -234,7 +652,7 @@ func (g *Game) CalculateScore(playerID int) int {
 	hasX2 := false
 
 	// Calculate base score
-	for _, card := range player.Cards {
+	for _, card := range p.Cards {
 		if card.CardType == "number" {
 			score += card.Value
 		} else if card.CardType == "modifier" {
@@ -252,7 +670,7 @@ func (g *Game) CalculateScore(playerID int) int {
 	}
 
 	// Add Flip 7 bonus (cannot be doubled)
-	if g.HasFlip7(playerID) {
+	if len(p.UniqueValues()) == 7 {
 		score += 15
 	}
 
@@ -276,13 +694,110 @@ func (g *Game) IsRoundOver() bool {
 	return activePlayers == 0
 }
 
-// StartNewRound resets players for a new round
+// ScoreRound adds each player's round score to their game score and updates
+// the Flip 7 and deficit tallies used to break ties
+func (g *Game) ScoreRound() {
+	for i := range g.Players {
+		if g.HasFlip7(i) {
+			g.Players[i].Flip7s++
+		}
+		g.Players[i].GameScore += g.CalculateScore(i)
+	}
+
+	leaderScore := g.Players[g.Leaders()[0]].GameScore
+	for i := range g.Players {
+		deficit := leaderScore - g.Players[i].GameScore
+		if deficit > g.Players[i].MaxDeficit {
+			g.Players[i].MaxDeficit = deficit
+		}
+	}
+
+	if g.Events != nil {
+		scores, gameScores := g.playerScores()
+		hands := make([][]Card, len(g.Players))
+		for i, player := range g.Players {
+			hands[i] = append([]Card{}, player.Cards...)
+		}
+		g.Emit(Event{Type: "round_scored", Scores: scores, GameScores: gameScores, Hands: hands})
+	}
+}
+
+// Leaders returns the IDs of the players sharing the highest game score
+func (g *Game) Leaders() []int {
+	players := make([]int, len(g.Players))
+	for i := range players {
+		players[i] = i
+	}
+
+	return playersWithMost(players, func(playerID int) int {
+		return g.Players[playerID].GameScore
+	})
+}
+
+// CheckGameOver ends the game once the game config's end condition is met:
+// a player reaching the target score, the round limit, or the last of a fixed
+// number of rounds. The tie-breaker settles ties for the highest score.
+func (g *Game) CheckGameOver() bool {
+	leaders := g.Leaders()
+
+	if g.Config.FixedRounds > 0 {
+		if g.CurrentRound < g.Config.FixedRounds {
+			return false
+		}
+	} else if g.Players[leaders[0]].GameScore < g.Config.TargetScore {
+		if g.Config.MaxRounds == 0 || g.CurrentRound < g.Config.MaxRounds {
+			return false
+		}
+	}
+
+	winners := leaders
+	if len(leaders) > 1 {
+		g.TiedFinish = true
+
+		tieBreaker := g.TieBreaker
+		if tieBreaker == nil {
+			tieBreaker = SharedWinTieBreaker{}
+		}
+
+		winners = tieBreaker.BreakTie(g, leaders)
+		if len(winners) == 0 {
+			return false
+		}
+	}
+
+	winners = append([]int(nil), winners...)
+	sort.Ints(winners)
+	g.IsGameOver = true
+	g.Winners = winners
+	g.Winner = winners[0]
+
+	if g.Events != nil {
+		_, gameScores := g.playerScores()
+		g.Emit(Event{Type: "game_over", GameScores: gameScores, Winners: winners})
+	}
+	return true
+}
+
+// StartNewRound resets players for a new round, moving the cards in front of
+// them to the discard pile. After the first round the deal passes to the left.
 func (g *Game) StartNewRound() {
+	g.CurrentRound++
+	if g.CurrentRound > 1 {
+		g.Dealer = (g.Dealer + 1) % len(g.Players)
+	}
+
+	g.pending = nil
+	g.progress = roundProgress{}
 	for i := range g.Players {
+		g.DiscardPile = append(g.DiscardPile, g.Players[i].Cards...)
 		g.Players[i].Cards = make([]Card, 0)
 		g.Players[i].IsBust = false
 		g.Players[i].HasStood = false
 	}
+
+	if g.Events != nil {
+		g.Emit(Event{Type: "round_start", Dealer: intRef(g.Dealer)})
+	}
 }
 
 // GetCardsRemaining returns a map of remaining cards in the deck by value
@@ -301,39 +816,42 @@ func (g *Game) GetCardsRemaining() map[int]int {
 // GetGameState returns the current game state
 func (g *Game) GetGameState() GameState {
 	return GameState{
-		Players:     g.Players,
-		Deck:        g.Deck,
-		DiscardPile: g.DiscardPile,
+		Players:      g.Players,
+		Deck:         g.Deck,
+		DiscardPile:  g.DiscardPile,
+		CurrentRound: g.CurrentRound,
+		Dealer:       g.Dealer,
+		IsGameOver:   g.IsGameOver,
+		Winner:       g.Winner,
+		Winners:      g.Winners,
 	}
 }
 
 // PrintGameState prints the current state for debugging
 func (g *Game) PrintGameState() {
-	fmt.Printf("=== Game State ===\n")
-	fmt.Printf("Deck size: %d\n", len(g.Deck))
+	g.WriteGameState(os.Stdout)
+}
+
+// WriteGameState writes the state PrintGameState prints to w
+func (g *Game) WriteGameState(w io.Writer) {
+	fmt.Fprintf(w, "=== Game State ===\n")
+	fmt.Fprintf(w, "Round: %d, Dealer: Player %d\n", g.CurrentRound, g.Dealer)
+	fmt.Fprintf(w, "Deck size: %d\n", len(g.Deck))
 
 	for _, player := range g.Players {
-		fmt.Printf("Player %d: ", player.ID)
+		fmt.Fprintf(w, "Player %d: ", player.ID)
 		if player.IsBust {
-			fmt.Printf("BUST")
+			fmt.Fprintf(w, "BUST")
 		} else if player.HasStood {
-			fmt.Printf("STOOD (Score: %d)", g.CalculateScore(player.ID))
+			fmt.Fprintf(w, "STOOD (Score: %d)", g.CalculateScore(player.ID))
 		} else {
-			fmt.Printf("Cards: ")
+			fmt.Fprintf(w, "Cards: ")
 			for _, card := range player.Cards {
-				if card.CardType == "number" {
-					fmt.Printf("%d ", card.Value)
-				} else if card.CardType == "modifier" {
-					if card.IsX2 {
-						fmt.Printf("x2 ")
-					} else {
-						fmt.Printf("+%d ", card.Modifier)
-					}
-				}
+				fmt.Fprintf(w, "%s ", card)
 			}
-			fmt.Printf("(Score: %d)", g.CalculateScore(player.ID))
+			fmt.Fprintf(w, "(Score: %d)", g.CalculateScore(player.ID))
 		}
-		fmt.Printf("\n")
+		fmt.Fprintf(w, "\n")
 	}
-	fmt.Printf("================\n")
+	fmt.Fprintf(w, "================\n")
 }
@@ -1,26 +1,85 @@
 package game
 
 import (
+	"encoding/json"
+	"math/rand"
+	"reflect"
 	"testing"
 )
 
+// targetAlgorithm is a test algorithm that always stands and targets a fixed player
+type targetAlgorithm struct {
+	target int
+}
+
+func (a *targetAlgorithm) MakeDecision(obs Observation) Decision {
+	return Decision{Action: "stand"}
+}
+
+func (a *targetAlgorithm) ChooseTarget(obs Observation, card Card, candidates []int) int {
+	return a.target
+}
+
+func (a *targetAlgorithm) GetName() string {
+	return "Target"
+}
+
 func TestCreateDeck(t *testing.T) {
-	game := NewGame(2)
+	game := NewGame(2, OfficialDeck())
 	game.CreateDeck()
 
 	// Check that deck has the expected number of cards
 	// 0(1) + 1(1) + 2(2) + 3(3) + ... + 12(12) = 1+1+2+3+4+5+6+7+8+9+10+11+12 = 79
-	// Plus 6 modifier cards (+1, +2, +3 x2 each) + 1 x2 card = 7 modifier cards
-	expectedCards := 79 + 7 // number cards + modifiers
+	// Plus 5 modifier cards (+2, +4, +6, +8, +10) + 1 x2 card = 6 modifier cards
+	// Plus 3 each of Freeze, Flip Three and Second Chance action cards
+	expectedCards := 79 + 6 + 9 // number cards + modifiers + actions
 	if len(game.Deck) != expectedCards {
 		t.Errorf("Expected %d cards in deck, got %d", expectedCards, len(game.Deck))
 	}
 }
 
-func TestPlayerHit(t *testing.T) {
-	game := NewGame(2)
+func TestCreateLegacyDeck(t *testing.T) {
+	game := NewGame(2, LegacyDeck())
 	game.CreateDeck()
 
+	// 79 number cards + 6 modifier cards (+1, +2, +3 x2 each) + 1 x2 card, and no action cards
+	expectedCards := 79 + 7
+	if len(game.Deck) != expectedCards {
+		t.Errorf("Expected %d cards in deck, got %d", expectedCards, len(game.Deck))
+	}
+	for _, card := range game.Deck {
+		if card.CardType == "action" {
+			t.Fatalf("Expected no action cards in the legacy deck, got %s", card)
+		}
+	}
+}
+
+func TestDeckSpecByName(t *testing.T) {
+	for _, name := range []string{"official", "legacy"} {
+		spec, err := DeckSpecByName(name)
+		if err != nil {
+			t.Errorf("Unexpected error for %q: %v", name, err)
+		}
+		if spec.Name != name {
+			t.Errorf("Expected spec named %q, got %q", name, spec.Name)
+		}
+	}
+
+	if _, err := DeckSpecByName("unknown"); err == nil {
+		t.Error("Expected an error for an unknown deck")
+	}
+}
+
+func TestPlayerHit(t *testing.T) {
+	game := NewGame(2, OfficialDeck())
+
+	// Use a deck without action cards or duplicates so every draw lands in front of the player
+	game.Deck = []Card{
+		{Value: 1, CardType: "number"},
+		{Value: 2, CardType: "number"},
+		{Value: 3, CardType: "number"},
+	}
+
 	// Give player a card first
 	game.DealInitialCard()
 	initialCards := len(game.Players[0].Cards)
@@ -37,7 +96,7 @@ func TestPlayerHit(t *testing.T) {
 }
 
 func TestBustCondition(t *testing.T) {
-	game := NewGame(1)
+	game := NewGame(1, OfficialDeck())
 
 	// Manually create a scenario where player will bust
 	game.Players[0].Cards = []Card{
@@ -62,7 +121,7 @@ func TestBustCondition(t *testing.T) {
 }
 
 func TestFlip7Detection(t *testing.T) {
-	game := NewGame(1)
+	game := NewGame(1, OfficialDeck())
 
 	// Create a Flip 7 scenario
 	game.Players[0].Cards = []Card{
@@ -82,7 +141,7 @@ func TestFlip7Detection(t *testing.T) {
 }
 
 func TestScoreCalculation(t *testing.T) {
-	game := NewGame(1)
+	game := NewGame(1, OfficialDeck())
 
 	// Test basic scoring
 	game.Players[0].Cards = []Card{
@@ -100,7 +159,7 @@ func TestScoreCalculation(t *testing.T) {
 }
 
 func TestScoreWithX2Multiplier(t *testing.T) {
-	game := NewGame(1)
+	game := NewGame(1, OfficialDeck())
 
 	// Test x2 multiplier
 	game.Players[0].Cards = []Card{
@@ -118,7 +177,7 @@ func TestScoreWithX2Multiplier(t *testing.T) {
 }
 
 func TestFlip7Bonus(t *testing.T) {
-	game := NewGame(1)
+	game := NewGame(1, OfficialDeck())
 
 	// Test Flip 7 bonus (should not be doubled)
 	game.Players[0].Cards = []Card{
@@ -142,3 +201,702 @@ func TestFlip7Bonus(t *testing.T) {
 		t.Errorf("Expected score %d, got %d", expectedScore, score)
 	}
 }
+
+func TestFreezeTargetsChosenPlayer(t *testing.T) {
+	game := NewGame(2, OfficialDeck())
+	game.Algorithms = []Algorithm{&targetAlgorithm{target: 1}, &targetAlgorithm{target: 0}}
+
+	game.Players[0].Cards = []Card{{Value: 5, CardType: "number"}}
+	game.Players[1].Cards = []Card{{Value: 8, CardType: "number"}}
+	game.Deck = []Card{{CardType: "action", Action: "freeze"}}
+
+	game.PlayerHit(0)
+
+	if game.Players[0].HasStood {
+		t.Error("Drawing player should not be frozen")
+	}
+
+	if !game.Players[1].HasStood {
+		t.Error("Target player should be frozen")
+	}
+
+	if score := game.CalculateScore(1); score != 8 {
+		t.Errorf("Frozen player should bank their points, expected 8, got %d", score)
+	}
+}
+
+func TestFreezeSelfWhenOnlyActivePlayer(t *testing.T) {
+	game := NewGame(2, OfficialDeck())
+	game.Algorithms = []Algorithm{&targetAlgorithm{target: 1}, &targetAlgorithm{target: 0}}
+
+	game.Players[0].Cards = []Card{{Value: 5, CardType: "number"}}
+	game.Players[1].HasStood = true
+	game.Deck = []Card{{CardType: "action", Action: "freeze"}}
+
+	game.PlayerHit(0)
+
+	if !game.Players[0].HasStood {
+		t.Error("Only active player must freeze themselves")
+	}
+
+	if score := game.CalculateScore(0); score != 5 {
+		t.Errorf("Expected score 5, got %d", score)
+	}
+}
+
+func TestFlipThreeDrawsThreeCards(t *testing.T) {
+	game := NewGame(2, OfficialDeck())
+	game.Algorithms = []Algorithm{&targetAlgorithm{target: 1}, &targetAlgorithm{target: 0}}
+
+	game.Players[0].Cards = []Card{{Value: 5, CardType: "number"}}
+	game.Players[1].Cards = []Card{{Value: 8, CardType: "number"}}
+	game.Deck = []Card{
+		{CardType: "action", Action: "flip_three"},
+		{Value: 1, CardType: "number"},
+		{Value: 2, CardType: "number"},
+		{Value: 3, CardType: "number"},
+		{Value: 4, CardType: "number"},
+	}
+
+	game.PlayerHit(0)
+
+	if score := game.CalculateScore(1); score != 8+1+2+3 {
+		t.Errorf("Expected target score %d, got %d", 8+1+2+3, score)
+	}
+
+	if len(game.Deck) != 1 {
+		t.Errorf("Flip Three should draw exactly three cards, %d left in deck", len(game.Deck))
+	}
+}
+
+func TestFlipThreeStopsOnBust(t *testing.T) {
+	game := NewGame(2, OfficialDeck())
+	game.Algorithms = []Algorithm{&targetAlgorithm{target: 1}, &targetAlgorithm{target: 0}}
+
+	game.Players[0].Cards = []Card{{Value: 5, CardType: "number"}}
+	game.Players[1].Cards = []Card{{Value: 8, CardType: "number"}}
+	game.Deck = []Card{
+		{CardType: "action", Action: "flip_three"},
+		{Value: 8, CardType: "number"},
+		{Value: 2, CardType: "number"},
+	}
+
+	game.PlayerHit(0)
+
+	if !game.Players[1].IsBust {
+		t.Error("Target should bust on a duplicate")
+	}
+
+	if len(game.Deck) != 1 {
+		t.Errorf("Flip Three should stop after a bust, %d left in deck", len(game.Deck))
+	}
+}
+
+func TestFlipThreeResolvesRevealedActionsAfterSequence(t *testing.T) {
+	game := NewGame(2, OfficialDeck())
+	game.Algorithms = []Algorithm{&targetAlgorithm{target: 1}, &targetAlgorithm{target: 0}}
+
+	game.Players[0].Cards = []Card{{Value: 5, CardType: "number"}}
+	game.Players[1].Cards = []Card{{Value: 8, CardType: "number"}}
+	game.Deck = []Card{
+		{CardType: "action", Action: "flip_three"},
+		{CardType: "action", Action: "freeze"},
+		{Value: 2, CardType: "number"},
+		{Value: 3, CardType: "number"},
+	}
+
+	game.PlayerHit(0)
+
+	// Player 1 draws the Freeze during the sequence and plays it on player 0 afterwards
+	if !game.Players[0].HasStood {
+		t.Error("Freeze revealed during Flip Three should be resolved after the sequence")
+	}
+
+	if score := game.CalculateScore(1); score != 8+2+3 {
+		t.Errorf("Expected target score %d, got %d", 8+2+3, score)
+	}
+}
+
+func TestSecondChancePreventsBust(t *testing.T) {
+	game := NewGame(1, OfficialDeck())
+
+	game.Players[0].Cards = []Card{
+		{Value: 5, CardType: "number"},
+		{CardType: "action", Action: "second_chance"},
+	}
+	game.Deck = []Card{{Value: 5, CardType: "number"}}
+
+	game.PlayerHit(0)
+
+	if game.Players[0].IsBust {
+		t.Error("Second Chance should prevent the bust")
+	}
+
+	if game.Players[0].HasSecondChance() {
+		t.Error("Second Chance should be discarded after use")
+	}
+
+	if len(game.Players[0].Cards) != 1 || len(game.DiscardPile) != 2 {
+		t.Errorf("Expected 1 card kept and 2 discarded, got %d and %d", len(game.Players[0].Cards), len(game.DiscardPile))
+	}
+}
+
+func TestSecondSecondChanceIsGivenAway(t *testing.T) {
+	game := NewGame(3, OfficialDeck())
+	game.Algorithms = []Algorithm{&targetAlgorithm{target: 2}, &targetAlgorithm{target: 0}, &targetAlgorithm{target: 0}}
+
+	game.Players[0].Cards = []Card{{CardType: "action", Action: "second_chance"}}
+	game.Deck = []Card{{CardType: "action", Action: "second_chance"}}
+
+	game.PlayerHit(0)
+
+	if !game.Players[2].HasSecondChance() {
+		t.Error("Second Second Chance should be given to the chosen player")
+	}
+
+	if game.Players[1].HasSecondChance() {
+		t.Error("Only the chosen player should receive the Second Chance")
+	}
+}
+
+func TestSecondChanceDiscardedWhenNobodyCanTakeIt(t *testing.T) {
+	game := NewGame(2, OfficialDeck())
+
+	game.Players[0].Cards = []Card{{CardType: "action", Action: "second_chance"}}
+	game.Players[1].HasStood = true
+	game.Deck = []Card{{CardType: "action", Action: "second_chance"}}
+
+	game.PlayerHit(0)
+
+	if game.Players[1].HasSecondChance() {
+		t.Error("Players who have stood cannot receive a Second Chance")
+	}
+
+	if len(game.DiscardPile) != 1 {
+		t.Errorf("Second Chance should be discarded, discard pile has %d cards", len(game.DiscardPile))
+	}
+}
+
+func TestStartNewRoundDiscardsCards(t *testing.T) {
+	game := NewGame(2, OfficialDeck())
+
+	// Use number cards only so the opening deal leaves one card in front of each player
+	game.Deck = []Card{
+		{Value: 5, CardType: "number"},
+		{Value: 8, CardType: "number"},
+		{Value: 3, CardType: "number"},
+		{Value: 11, CardType: "number"},
+	}
+	deckSize := len(game.Deck)
+	game.DealInitialCard()
+
+	game.StartNewRound()
+
+	if len(game.DiscardPile) != 2 {
+		t.Errorf("Expected the 2 dealt cards in the discard pile, got %d", len(game.DiscardPile))
+	}
+
+	if len(game.Deck)+len(game.DiscardPile) != deckSize {
+		t.Error("Cards should be conserved across rounds")
+	}
+}
+
+func TestReshuffleLeavesCardsInPlay(t *testing.T) {
+	game := NewGame(1, OfficialDeck())
+
+	game.Players[0].Cards = []Card{{Value: 5, CardType: "number"}}
+	game.DiscardPile = []Card{{Value: 7, CardType: "number"}}
+	game.Deck = []Card{}
+
+	game.PlayerHit(0)
+
+	if len(game.Players[0].Cards) != 2 || game.Players[0].Cards[1].Value != 7 {
+		t.Error("Reshuffle should only use the discard pile")
+	}
+
+	if len(game.Deck) != 0 || len(game.DiscardPile) != 0 {
+		t.Error("Cards in play should not be reshuffled into the deck")
+	}
+}
+
+func TestTurnOrderStartsLeftOfDealer(t *testing.T) {
+	game := NewGame(4, OfficialDeck())
+	game.Dealer = 1
+
+	expected := []int{2, 3, 0, 1}
+	for i, playerID := range game.TurnOrder() {
+		if playerID != expected[i] {
+			t.Fatalf("Expected turn order %v, got %v", expected, game.TurnOrder())
+		}
+	}
+}
+
+func TestDealerRotatesEachRound(t *testing.T) {
+	game := NewGame(3, OfficialDeck())
+
+	// Player 0 acts first in the opening round by default
+	game.StartNewRound()
+	if game.TurnOrder()[0] != 0 {
+		t.Errorf("Expected player 0 to act first in round 1, got %d", game.TurnOrder()[0])
+	}
+
+	game.StartNewRound()
+	if game.Dealer != 0 || game.TurnOrder()[0] != 1 {
+		t.Errorf("Expected the deal to pass to player 0 in round 2, got dealer %d", game.Dealer)
+	}
+}
+
+func TestCheckGameOverBelowTarget(t *testing.T) {
+	game := NewGame(2, OfficialDeck())
+	game.Players[0].GameScore = 150
+
+	if game.CheckGameOver() {
+		t.Error("Game should not end before anyone reaches the target")
+	}
+
+	if game.GetGameState().Winner != -1 {
+		t.Error("Winner should not be set while the game is in progress")
+	}
+}
+
+func TestCheckGameOverTieBreakers(t *testing.T) {
+	tests := []struct {
+		tieBreaker TieBreaker
+		winners    []int
+		over       bool
+	}{
+		{SharedWinTieBreaker{}, []int{1, 2}, true},
+		{MostFlip7sTieBreaker{}, []int{2}, true},
+		{ComebackTieBreaker{}, []int{1}, true},
+		{ExtraRoundTieBreaker{}, nil, false},
+	}
+
+	for _, tt := range tests {
+		game := NewGame(3, OfficialDeck())
+		game.TieBreaker = tt.tieBreaker
+		game.Players[0].GameScore = 180
+		game.Players[1].GameScore = 210
+		game.Players[1].MaxDeficit = 60
+		game.Players[2].GameScore = 210
+		game.Players[2].Flip7s = 2
+
+		if over := game.CheckGameOver(); over != tt.over {
+			t.Errorf("%s: expected game over %v, got %v", tt.tieBreaker.GetName(), tt.over, over)
+			continue
+		}
+
+		if !tt.over {
+			continue
+		}
+
+		state := game.GetGameState()
+		if len(state.Winners) != len(tt.winners) || state.Winner != tt.winners[0] {
+			t.Errorf("%s: expected winners %v, got %v", tt.tieBreaker.GetName(), tt.winners, state.Winners)
+		}
+	}
+}
+
+func TestScoreRoundTracksDeficits(t *testing.T) {
+	game := NewGame(2, OfficialDeck())
+	game.Players[0].Cards = []Card{{Value: 12, CardType: "number"}}
+	game.Players[1].Cards = []Card{{Value: 2, CardType: "number"}}
+
+	game.ScoreRound()
+
+	if game.Players[0].GameScore != 12 || game.Players[1].GameScore != 2 {
+		t.Errorf("Unexpected game scores %d and %d", game.Players[0].GameScore, game.Players[1].GameScore)
+	}
+
+	if game.Players[1].MaxDeficit != 10 || game.Players[0].MaxDeficit != 0 {
+		t.Errorf("Expected deficits 0 and 10, got %d and %d", game.Players[0].MaxDeficit, game.Players[1].MaxDeficit)
+	}
+}
+
+func TestCheckGameOverRoundLimits(t *testing.T) {
+	game := NewGame(2, OfficialDeck())
+	game.Config = GameConfig{TargetScore: 200, MaxRounds: 3}
+	game.Players[1].GameScore = 50
+	game.CurrentRound = 2
+
+	if game.CheckGameOver() {
+		t.Error("Game should continue before the round limit")
+	}
+
+	game.CurrentRound = 3
+	if !game.CheckGameOver() || game.Winner != 1 {
+		t.Error("Game should end at the round limit with the highest total winning")
+	}
+
+	game = NewGame(2, OfficialDeck())
+	game.Config = GameConfig{TargetScore: 200, FixedRounds: 5}
+	game.Players[0].GameScore = 250
+	game.CurrentRound = 4
+
+	if game.CheckGameOver() {
+		t.Error("Fixed-round games should ignore the target score")
+	}
+}
+
+func TestObservationCountsUnseenCards(t *testing.T) {
+	game := NewGame(3, OfficialDeck())
+	game.CreateDeck()
+	game.DealInitialCard()
+
+	obs := game.Observe(0)
+
+	// Everything not face-up is unseen, which is exactly what is left in the deck
+	if obs.UnseenCount() != len(game.Deck) {
+		t.Errorf("Expected %d unseen cards, got %d", len(game.Deck), obs.UnseenCount())
+	}
+
+	deckNumbers := game.GetCardsRemaining()
+	for value, count := range obs.UnseenNumbers() {
+		if deckNumbers[value] != count {
+			t.Errorf("Expected %d unseen %ds, got %d", deckNumbers[value], value, count)
+		}
+	}
+}
+
+func TestObservationIsACopy(t *testing.T) {
+	game := NewGame(2, OfficialDeck())
+	game.Players[0].Cards = []Card{{Value: 5, CardType: "number"}}
+	game.DiscardPile = []Card{{Value: 7, CardType: "number"}}
+
+	obs := game.Observe(0)
+	obs.Self().Cards[0].Value = 12
+	obs.Players()[0].Cards[0].Value = 12
+	obs.DiscardPile()[0].Value = 12
+	obs.Unseen()[Card{Value: 12, CardType: "number"}] = 0
+
+	if game.Players[0].Cards[0].Value != 5 || game.DiscardPile[0].Value != 7 {
+		t.Error("Changing an observation should not change the game")
+	}
+
+	if obs.Self().Cards[0].Value != 5 || obs.UnseenNumbers()[12] != 12 {
+		t.Error("Observations should not be changed through their accessors")
+	}
+}
+
+// legacyRecorder records what a legacy algorithm is handed
+type legacyRecorder struct {
+	gameState      GameState
+	cardsRemaining map[int]int
+}
+
+func (a *legacyRecorder) MakeDecision(playerState PlayerState, gameState GameState, cardsRemaining map[int]int) Decision {
+	a.gameState = gameState
+	a.cardsRemaining = cardsRemaining
+	return Decision{Action: "stand"}
+}
+
+func (a *legacyRecorder) ChooseTarget(playerState PlayerState, gameState GameState, card Card, candidates []int) int {
+	return playerState.ID
+}
+
+func (a *legacyRecorder) GetName() string {
+	return "Legacy"
+}
+
+func TestLegacyAdapterHidesDeck(t *testing.T) {
+	game := NewGame(1, OfficialDeck())
+	game.Deck = []Card{{Value: 3, CardType: "number"}}
+
+	recorder := &legacyRecorder{}
+	game.Algorithms = []Algorithm{FromLegacy(recorder)}
+	game.StartNewRound()
+	game.PlayRound()
+
+	if recorder.gameState.Deck != nil {
+		t.Error("Legacy algorithms should not be handed the deck")
+	}
+
+	if recorder.cardsRemaining[12] != 12 || recorder.cardsRemaining[3] != 2 {
+		t.Error("Legacy algorithms should be handed the unseen number counts")
+	}
+}
+
+func TestSeededGamesShuffleIdentically(t *testing.T) {
+	first := NewSeededGame(2, OfficialDeck(), 42)
+	second := NewSeededGame(2, OfficialDeck(), 42)
+	first.CreateDeck()
+	second.CreateDeck()
+
+	for i := range first.Deck {
+		if first.Deck[i] != second.Deck[i] {
+			t.Fatalf("Decks from the same seed differ at card %d", i)
+		}
+	}
+}
+
+func TestRoundDealIndependentOfEarlierRounds(t *testing.T) {
+	first := NewSeededGame(2, OfficialDeck(), 42)
+	second := NewSeededGame(2, OfficialDeck(), 42)
+
+	// The first game reshuffles mid-round before reaching round 3; the second jumps straight there
+	first.CurrentRound = 1
+	first.CreateDeck()
+	first.DiscardPile = append(first.DiscardPile, first.Deck...)
+	first.Deck = nil
+	first.ReshuffleDeck()
+
+	first.CurrentRound = 3
+	second.CurrentRound = 3
+	first.CreateDeck()
+	second.CreateDeck()
+
+	if !reflect.DeepEqual(first.Deck, second.Deck) {
+		t.Error("Round 3 should be dealt the same deck regardless of earlier shuffles")
+	}
+
+	second.CurrentRound = 4
+	second.CreateDeck()
+	if reflect.DeepEqual(first.Deck, second.Deck) {
+		t.Error("Different rounds should be dealt differently shuffled decks")
+	}
+}
+
+func TestEventsRecordDecisionsAndActions(t *testing.T) {
+	game := NewGame(2, OfficialDeck())
+	game.Algorithms = []Algorithm{&targetAlgorithm{target: 1}, &targetAlgorithm{target: 0}}
+	recorder := &EventRecorder{}
+	game.Events = recorder
+
+	game.StartNewRound()
+	game.Deck = []Card{
+		{Value: 5, CardType: "number"},
+		{Value: 8, CardType: "number"},
+		{CardType: "action", Action: "freeze"},
+	}
+	game.DealInitialCard()
+	game.PlayerHit(0)
+
+	var types []string
+	for _, event := range recorder.Events {
+		types = append(types, event.Type)
+	}
+	expected := []string{"round_start", "card_dealt", "card_dealt", "card_dealt", "action"}
+	if !reflect.DeepEqual(types, expected) {
+		t.Fatalf("Expected events %v, got %v", expected, types)
+	}
+
+	action := recorder.Events[4]
+	if *action.Player != 0 || *action.Target != 1 || action.Observation == nil {
+		t.Errorf("Expected player 0 to freeze player 1 with the observation recorded, got %+v", action)
+	}
+	if action.Round != 1 {
+		t.Errorf("Expected events to be stamped with round 1, got %d", action.Round)
+	}
+}
+
+func TestEventsCoverAWholeGame(t *testing.T) {
+	game := NewSeededGame(2, OfficialDeck(), 7)
+	game.Algorithms = []Algorithm{&targetAlgorithm{target: 1}, &targetAlgorithm{target: 0}}
+	recorder := &EventRecorder{}
+	game.Events = recorder
+	game.Config.FixedRounds = 2
+
+	for !game.IsGameOver {
+		game.StartNewRound()
+		game.CreateDeck()
+		game.PlayRound()
+		game.ScoreRound()
+		game.CheckGameOver()
+	}
+
+	counts := make(map[string]int)
+	for _, event := range recorder.Events {
+		counts[event.Type]++
+		if (event.Type == "hit" || event.Type == "stand") && event.Observation == nil {
+			t.Errorf("Decision event is missing the observation: %+v", event)
+		}
+	}
+
+	if counts["round_start"] != 2 || counts["round_scored"] != 2 || counts["game_over"] != 1 {
+		t.Errorf("Expected 2 rounds started and scored and one game over, got %v", counts)
+	}
+	if last := recorder.Events[len(recorder.Events)-1]; last.Type != "game_over" || !reflect.DeepEqual(last.Winners, game.Winners) {
+		t.Errorf("Expected the game to end with a game_over naming the winners, got %+v", last)
+	}
+}
+
+func TestObservationJSONRoundTrip(t *testing.T) {
+	game := NewSeededGame(3, OfficialDeck(), 11)
+	game.CreateDeck()
+	game.DealInitialCard()
+	obs := game.Observe(1)
+
+	data, err := json.Marshal(obs)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var decoded Observation
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if decoded.PlayerID() != 1 || !reflect.DeepEqual(decoded.Players(), obs.Players()) {
+		t.Error("Expected the players to survive a round trip")
+	}
+	if !reflect.DeepEqual(decoded.Unseen(), obs.Unseen()) || decoded.UnseenCount() != obs.UnseenCount() {
+		t.Error("Expected the unseen counts to survive a round trip")
+	}
+}
+
+// hitBelowAlgorithm is a test algorithm that hits until its round score reaches a threshold
+type hitBelowAlgorithm struct {
+	threshold int
+}
+
+func (a *hitBelowAlgorithm) MakeDecision(obs Observation) Decision {
+	if obs.Self().RoundScore() < a.threshold {
+		return Decision{Action: "hit"}
+	}
+	return Decision{Action: "stand"}
+}
+
+func (a *hitBelowAlgorithm) ChooseTarget(obs Observation, card Card, candidates []int) int {
+	return candidates[0]
+}
+
+func (a *hitBelowAlgorithm) GetName() string {
+	return "Hit Below"
+}
+
+func TestApplyMoveMatchesPlayRound(t *testing.T) {
+	algorithms := []Algorithm{&hitBelowAlgorithm{threshold: 20}, &hitBelowAlgorithm{threshold: 15}, &hitBelowAlgorithm{threshold: 25}}
+
+	played := NewSeededGame(3, OfficialDeck(), 5)
+	played.Algorithms = algorithms
+	playedEvents := &EventRecorder{}
+	played.Events = playedEvents
+	played.StartNewRound()
+	played.CreateDeck()
+	played.PlayRound()
+
+	continued := NewSeededGame(3, OfficialDeck(), 5)
+	continued.Algorithms = algorithms
+	continuedEvents := &EventRecorder{}
+	continued.Events = continuedEvents
+	continued.StartNewRound()
+	continued.CreateDeck()
+	continued.BeginRound()
+
+	// Take the first decision by hand, then let the game carry on from it
+	playerID := continued.ToMove()
+	decision := algorithms[playerID].MakeDecision(continued.Observe(playerID))
+	if err := continued.ApplyMove(Move{Action: decision.Action}); err != nil {
+		t.Fatalf("ApplyMove failed: %v", err)
+	}
+	continued.FinishRound()
+
+	if !reflect.DeepEqual(continued.Players, played.Players) {
+		t.Errorf("Expected the same hands as PlayRound, got %+v and %+v", continued.Players, played.Players)
+	}
+	if !reflect.DeepEqual(continuedEvents.Events, playedEvents.Events) {
+		t.Errorf("Expected the same %d events as PlayRound, got %d", len(playedEvents.Events), len(continuedEvents.Events))
+	}
+}
+
+func TestMovesWaitOnActionTargets(t *testing.T) {
+	game := NewGame(2, OfficialDeck())
+	game.StartNewRound()
+	game.Deck = []Card{
+		{Value: 5, CardType: "number"},
+		{Value: 8, CardType: "number"},
+		{CardType: "action", Action: "flip_three"},
+		{Value: 1, CardType: "number"},
+		{Value: 2, CardType: "number"},
+		{Value: 3, CardType: "number"},
+	}
+	game.BeginRound()
+
+	if game.ToMove() != 0 || !reflect.DeepEqual(game.LegalMoves(), []Move{{Action: "hit"}, {Action: "stand"}}) {
+		t.Fatalf("Expected player 0 to hit or stand, got player %d with %v", game.ToMove(), game.LegalMoves())
+	}
+	if err := game.ApplyMove(Move{Action: "target", Target: 1}); err == nil {
+		t.Error("Expected a target to be illegal on a player's turn")
+	}
+
+	game.ApplyMove(Move{Action: "hit"})
+	expected := []Move{{Action: "target", Target: 0}, {Action: "target", Target: 1}}
+	if card, ok := game.ChoiceCard(); game.ToMove() != 0 || !ok || card.Action != "flip_three" || !reflect.DeepEqual(game.LegalMoves(), expected) {
+		t.Fatalf("Expected player 0 to choose a Flip Three target, got player %d with %v", game.ToMove(), game.LegalMoves())
+	}
+
+	game.ApplyMove(Move{Action: "target", Target: 1})
+	if len(game.Players[1].Cards) != 5 {
+		t.Errorf("Expected player 1 to take the Flip Three and three cards, got %v", game.Players[1].Cards)
+	}
+	if game.ToMove() != 1 {
+		t.Errorf("Expected play to pass to player 1, got %d", game.ToMove())
+	}
+}
+
+func TestCloneSharesNoState(t *testing.T) {
+	game := NewSeededGame(2, OfficialDeck(), 3)
+	game.Events = &EventRecorder{}
+	game.CreateDeck()
+	game.DealInitialCard()
+
+	deckSize := len(game.Deck)
+	cards := len(game.Players[0].Cards)
+
+	clone := game.Clone()
+	if clone.Events != nil {
+		t.Error("Expected a clone to log no events")
+	}
+	clone.PlayerHit(0)
+	clone.Players[1].GameScore = 50
+
+	if len(game.Deck) != deckSize || len(game.Players[0].Cards) != cards || game.Players[1].GameScore != 0 {
+		t.Error("Expected changes to the clone to leave the original untouched")
+	}
+}
+
+func TestDeterminizeMatchesObservation(t *testing.T) {
+	game := NewSeededGame(3, OfficialDeck(), 9)
+	game.CreateDeck()
+	game.DealInitialCard()
+	game.PlayerHit(0)
+	obs := game.Observe(0)
+
+	determinized := obs.Determinize(rand.New(rand.NewSource(1)))
+	if len(determinized.Deck) != obs.UnseenCount() {
+		t.Errorf("Expected the %d unseen cards in the deck, got %d", obs.UnseenCount(), len(determinized.Deck))
+	}
+	if determinized.Spec.Size() != game.Spec.Size() {
+		t.Errorf("Expected the rebuilt spec to hold %d cards, got %d", game.Spec.Size(), determinized.Spec.Size())
+	}
+
+	again := determinized.Observe(0)
+	if !reflect.DeepEqual(again.Unseen(), obs.Unseen()) || !reflect.DeepEqual(again.Players(), obs.Players()) {
+		t.Error("Expected the determinized game to look the same to the observing player")
+	}
+}
+
+func TestDeterminizeWaitsOnTheSameDecision(t *testing.T) {
+	game := NewGame(3, OfficialDeck())
+	game.StartNewRound()
+	game.Deck = []Card{
+		{Value: 5, CardType: "number"},
+		{CardType: "action", Action: "freeze"},
+		{Value: 8, CardType: "number"},
+	}
+	game.BeginRound()
+	obs := game.Observe(game.ToMove())
+
+	determinized := obs.Determinize(rand.New(rand.NewSource(1)))
+	if determinized.ToMove() != 1 || !reflect.DeepEqual(determinized.LegalMoves(), game.LegalMoves()) {
+		t.Fatalf("Expected player 1 to choose a Freeze target from %v, got player %d with %v", game.LegalMoves(), determinized.ToMove(), determinized.LegalMoves())
+	}
+	if len(determinized.Deck) != obs.UnseenCount() || determinized.Spec.Size() != game.Spec.Size() {
+		t.Errorf("Expected the Freeze to be counted as seen, got %d cards in the deck and %d in the spec", len(determinized.Deck), determinized.Spec.Size())
+	}
+
+	determinized.ApplyMove(Move{Action: "target", Target: 0})
+	// Player 2 is dealt their opening card, then frozen player 0 is skipped
+	if !determinized.Players[0].HasStood || len(determinized.Players[2].Cards) != 1 || determinized.ToMove() != 1 {
+		t.Errorf("Expected player 0 frozen, player 2 dealt in and player 1 to move, got %+v waiting on %d", determinized.Players, determinized.ToMove())
+	}
+}
@@ -0,0 +1,127 @@
+package game
+
+import "fmt"
+
+// Move is one decision a player can make: to hit or stand on their turn, or
+// which player an action card they drew is played on. Together with
+// BeginRound, ToMove, LegalMoves and ApplyMove it lets a round be played one
+// decision at a time, by a search as well as by the algorithms.
+type Move struct {
+	Action string `json:"action"`           // "hit", "stand" or "target"
+	Target int    `json:"target,omitempty"` // Player the action card is played on, for "target"
+}
+
+// String shows a move the way it is printed in search output: "hit", "stand" or "target 2"
+func (m Move) String() string {
+	if m.Action == "target" {
+		return fmt.Sprintf("target %d", m.Target)
+	}
+	return m.Action
+}
+
+// roundProgress is where play has got to in a round, so it can stop at any
+// decision and carry on from it later
+type roundProgress struct {
+	dealt  int        // Players in turn order dealt their opening card so far
+	turn   int        // Position in turn order of the next player to hit or stand
+	flip   *flipThree // Flip Three being drawn
+	choice *choice    // Decision play is waiting on
+}
+
+// flipThree is a Flip Three partway through being drawn
+type flipThree struct {
+	target int
+	left   int             // Cards still to draw
+	queued []pendingAction // Actions waiting from before the Flip Three, resolved after it
+}
+
+// choice is a decision play is waiting on: a hit or stand, or the target of an action card
+type choice struct {
+	playerID   int
+	card       *Card // Action card to be played, or nil for a hit or stand
+	candidates []int // Players the action card can be played on
+}
+
+// copy returns a copy of the progress that shares no memory with the original
+func (p roundProgress) copy() roundProgress {
+	if p.flip != nil {
+		flip := *p.flip
+		flip.queued = append([]pendingAction(nil), flip.queued...)
+		p.flip = &flip
+	}
+	if p.choice != nil {
+		choice := *p.choice
+		if choice.card != nil {
+			card := *choice.card
+			choice.card = &card
+		}
+		choice.candidates = append([]int(nil), choice.candidates...)
+		p.choice = &choice
+	}
+	return p
+}
+
+// BeginRound deals the opening cards and plays up to the round's first
+// decision, for playing the round one move at a time with ApplyMove
+func (g *Game) BeginRound() {
+	g.progress = roundProgress{}
+	g.advance()
+}
+
+// ToMove returns the ID of the player play is waiting on, or -1 once the round is over
+func (g *Game) ToMove() int {
+	if g.progress.choice == nil {
+		return -1
+	}
+	return g.progress.choice.playerID
+}
+
+// LegalMoves returns the moves open to the player play is waiting on, or nil
+// once the round is over. A hit is always legal on a player's turn, even
+// with no cards left to draw; the player then stands.
+func (g *Game) LegalMoves() []Move {
+	choice := g.progress.choice
+	if choice == nil {
+		return nil
+	}
+
+	if choice.card == nil {
+		return []Move{{Action: "hit"}, {Action: "stand"}}
+	}
+
+	moves := make([]Move, len(choice.candidates))
+	for i, candidate := range choice.candidates {
+		moves[i] = Move{Action: "target", Target: candidate}
+	}
+	return moves
+}
+
+// ChoiceCard returns the action card play is waiting on a target for, if any
+func (g *Game) ChoiceCard() (Card, bool) {
+	if g.progress.choice == nil || g.progress.choice.card == nil {
+		return Card{}, false
+	}
+	return *g.progress.choice.card, true
+}
+
+// ApplyMove makes a move for the player play is waiting on, then plays on to
+// the next decision or the end of the round
+func (g *Game) ApplyMove(move Move) error {
+	legal := false
+	for _, candidate := range g.LegalMoves() {
+		legal = legal || candidate == move
+	}
+	if !legal {
+		return fmt.Errorf("%s is not a legal move", move)
+	}
+
+	var obs *Observation
+	if g.Events != nil {
+		seen := g.Observe(g.progress.choice.playerID)
+		obs = &seen
+	}
+
+	g.playMove(move, obs)
+	g.advance()
+	return nil
+}
@@ -0,0 +1,216 @@
+package game
+
+// Observation is everything a player is allowed to see when making a decision:
+// the face-up cards and scores of every player, the discard pile, the seating
+// and counts of the cards nobody has seen yet. It never reveals the deck order.
+// Observations are built fresh for each decision and their accessors return
+// copies, so an algorithm cannot change the game through one.
+type Observation struct {
+	playerID    int
+	players     []PlayerState
+	discardPile []Card
+	kinds       []Card // Distinct cards in the deck spec, shared read-only with the game
+	unseen      []int  // Unseen count of each card in kinds
+	turnOrder   []int
+	dealer      int
+	round       int
+	config      GameConfig
+	pending     []pendingAction // Face-up action cards waiting to be resolved
+	progress    roundProgress   // Where play has got to in the round, for Determinize
+}
+
+// Observe builds the observation for the given player from the public state of the game
+func (g *Game) Observe(playerID int) Observation {
+	players := make([]PlayerState, len(g.Players))
+	for i, player := range g.Players {
+		players[i] = copyPlayer(player)
+	}
+
+	// The full deck composition only needs working out once per game
+	if g.cardKinds == nil {
+		g.cardKinds, g.kindCounts = g.Spec.kinds()
+	}
+
+	obs := Observation{
+		playerID:    playerID,
+		players:     players,
+		discardPile: append([]Card(nil), g.DiscardPile...),
+		kinds:       g.cardKinds,
+		unseen:      append([]int(nil), g.kindCounts...),
+		turnOrder:   g.TurnOrder(),
+		dealer:      g.Dealer,
+		round:       g.CurrentRound,
+		config:      g.Config,
+		pending:     append([]pendingAction(nil), g.pending...),
+		progress:    g.progress.copy(),
+	}
+
+	// Unseen cards are worked out from what has been seen, never from the deck itself
+	for _, player := range g.Players {
+		for _, card := range player.Cards {
+			obs.markSeen(card)
+		}
+	}
+	for _, card := range g.DiscardPile {
+		obs.markSeen(card)
+	}
+	for _, action := range g.pending {
+		obs.markSeen(action.card)
+	}
+	if flip := g.progress.flip; flip != nil {
+		for _, action := range flip.queued {
+			obs.markSeen(action.card)
+		}
+	}
+	if choice := g.progress.choice; choice != nil && choice.card != nil {
+		obs.markSeen(*choice.card)
+	}
+
+	return obs
+}
+
+// markSeen removes a face-up card from the unseen counts
+func (o Observation) markSeen(card Card) {
+	for i, kind := range o.kinds {
+		if kind == card {
+			if o.unseen[i] > 0 {
+				o.unseen[i]--
+			}
+			return
+		}
+	}
+}
+
+// PlayerID returns the ID of the observing player
+func (o Observation) PlayerID() int {
+	return o.playerID
+}
+
+// Self returns the observing player's own state
+func (o Observation) Self() PlayerState {
+	return copyPlayer(o.players[o.playerID])
+}
+
+// Player returns the public state of any player
+func (o Observation) Player(playerID int) PlayerState {
+	return copyPlayer(o.players[playerID])
+}
+
+// Players returns the public state of every player, indexed by player ID
+func (o Observation) Players() []PlayerState {
+	players := make([]PlayerState, len(o.players))
+	for i, player := range o.players {
+		players[i] = copyPlayer(player)
+	}
+	return players
+}
+
+// NumPlayers returns the number of players at the table
+func (o Observation) NumPlayers() int {
+	return len(o.players)
+}
+
+// DiscardPile returns the face-up discard pile
+func (o Observation) DiscardPile() []Card {
+	return append([]Card(nil), o.discardPile...)
+}
+
+// TurnOrder returns player IDs in this round's turn order
+func (o Observation) TurnOrder() []int {
+	return append([]int(nil), o.turnOrder...)
+}
+
+// Dealer returns the player ID of this round's dealer
+func (o Observation) Dealer() int {
+	return o.dealer
+}
+
+// Round returns the current round number, starting at 1
+func (o Observation) Round() int {
+	return o.round
+}
+
+// Config returns the game length settings
+func (o Observation) Config() GameConfig {
+	return o.config
+}
+
+// Unseen returns how many of each card have not been seen, either in the deck
+// or yet to come back from a reshuffle
+func (o Observation) Unseen() map[Card]int {
+	unseen := make(map[Card]int, len(o.kinds))
+	for i, count := range o.unseen {
+		if count > 0 {
+			unseen[o.kinds[i]] = count
+		}
+	}
+	return unseen
+}
+
+// UnseenNumbers returns how many unseen number cards there are of each value
+func (o Observation) UnseenNumbers() map[int]int {
+	numbers := make(map[int]int)
+	for i, count := range o.unseen {
+		if o.kinds[i].CardType == "number" && count > 0 {
+			numbers[o.kinds[i].Value] += count
+		}
+	}
+	return numbers
+}
+
+// UnseenCount returns the total number of unseen cards
+func (o Observation) UnseenCount() int {
+	total := 0
+	for _, count := range o.unseen {
+		total += count
+	}
+	return total
+}
+
+// copyPlayer returns a copy of a player's state that shares no memory with the original
+func copyPlayer(player PlayerState) PlayerState {
+	player.Cards = append([]Card(nil), player.Cards...)
+	return player
+}
+
+// LegacyAlgorithm is the original algorithm interface, which is handed the game state directly
+type LegacyAlgorithm interface {
+	MakeDecision(playerState PlayerState, gameState GameState, cardsRemaining map[int]int) Decision
+	ChooseTarget(playerState PlayerState, gameState GameState, card Card, candidates []int) int
+	GetName() string
+}
+
+// FromLegacy adapts an algorithm written against the original interface. The
+// game state it is given has no deck, and cardsRemaining counts the unseen
+// number cards, so it sees only what an Observation does.
+func FromLegacy(algorithm LegacyAlgorithm) Algorithm {
+	return &legacyAdapter{legacy: algorithm}
+}
+
+// legacyAdapter implements Algorithm on top of a LegacyAlgorithm
+type legacyAdapter struct {
+	legacy LegacyAlgorithm
+}
+
+func (a *legacyAdapter) MakeDecision(obs Observation) Decision {
+	return a.legacy.MakeDecision(obs.Self(), legacyGameState(obs), obs.UnseenNumbers())
+}
+
+func (a *legacyAdapter) ChooseTarget(obs Observation, card Card, candidates []int) int {
+	return a.legacy.ChooseTarget(obs.Self(), legacyGameState(obs), card, append([]int(nil), candidates...))
+}
+
+func (a *legacyAdapter) GetName() string {
+	return a.legacy.GetName()
+}
+
+// legacyGameState builds the original GameState from an observation, leaving the deck out
+func legacyGameState(obs Observation) GameState {
+	return GameState{
+		Players:      obs.Players(),
+		DiscardPile:  obs.DiscardPile(),
+		CurrentRound: obs.Round(),
+		Dealer:       obs.Dealer(),
+		Winner:       -1,
+	}
+}
@@ -0,0 +1,95 @@
+package game
+
+import "fmt"
+
+// TieBreaker decides the winner when several players finish level on the highest score
+type TieBreaker interface {
+	// BreakTie returns the winning player IDs from the tied players. An empty
+	// result leaves the tie unsettled, and another round is played.
+	BreakTie(g *Game, tied []int) []int
+	GetName() string
+}
+
+// SharedWinTieBreaker lets every tied player share the win
+type SharedWinTieBreaker struct{}
+
+func (t SharedWinTieBreaker) BreakTie(g *Game, tied []int) []int {
+	return tied
+}
+
+func (t SharedWinTieBreaker) GetName() string {
+	return "shared"
+}
+
+// MostFlip7sTieBreaker gives the win to the tied player with the most Flip 7s this game.
+// Players still level on Flip 7s share the win.
+type MostFlip7sTieBreaker struct{}
+
+func (t MostFlip7sTieBreaker) BreakTie(g *Game, tied []int) []int {
+	return playersWithMost(tied, func(playerID int) int {
+		return g.Players[playerID].Flip7s
+	})
+}
+
+func (t MostFlip7sTieBreaker) GetName() string {
+	return "flip7s"
+}
+
+// ComebackTieBreaker gives the win to the tied player who came from furthest behind,
+// measured by their largest deficit to the leader at the end of any round.
+// Players still level share the win.
+type ComebackTieBreaker struct{}
+
+func (t ComebackTieBreaker) BreakTie(g *Game, tied []int) []int {
+	return playersWithMost(tied, func(playerID int) int {
+		return g.Players[playerID].MaxDeficit
+	})
+}
+
+func (t ComebackTieBreaker) GetName() string {
+	return "comeback"
+}
+
+// ExtraRoundTieBreaker plays another round until the tie is broken
+type ExtraRoundTieBreaker struct{}
+
+func (t ExtraRoundTieBreaker) BreakTie(g *Game, tied []int) []int {
+	return nil
+}
+
+func (t ExtraRoundTieBreaker) GetName() string {
+	return "extra-round"
+}
+
+// TieBreakerByName returns the tie-breaker with the given name
+func TieBreakerByName(name string) (TieBreaker, error) {
+	for _, tieBreaker := range []TieBreaker{
+		SharedWinTieBreaker{},
+		MostFlip7sTieBreaker{},
+		ComebackTieBreaker{},
+		ExtraRoundTieBreaker{},
+	} {
+		if tieBreaker.GetName() == name {
+			return tieBreaker, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown tie-breaker %q (want shared, flip7s, comeback or extra-round)", name)
+}
+
+// playersWithMost returns the players with the highest value of the given measure
+func playersWithMost(players []int, measure func(playerID int) int) []int {
+	best := make([]int, 0, len(players))
+	bestValue := 0
+
+	for _, playerID := range players {
+		value := measure(playerID)
+		if len(best) == 0 || value > bestValue {
+			best = []int{playerID}
+			bestValue = value
+		} else if value == bestValue {
+			best = append(best, playerID)
+		}
+	}
+
+	return best
+}
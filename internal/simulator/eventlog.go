@@ -0,0 +1,131 @@
+package simulator
+
+import (
+	"encoding/json"
+	"errors"
+	"flip7-simulator/internal/game"
+	"fmt"
+	"io"
+)
+
+// EventLog writes game events as JSON Lines, one event per line
+type EventLog struct {
+	encoder *json.Encoder
+	err     error
+}
+
+// NewEventLog creates an event log writing to w
+func NewEventLog(w io.Writer) *EventLog {
+	return &EventLog{encoder: json.NewEncoder(w)}
+}
+
+// Write logs every event of a game, tagged with its game number
+func (l *EventLog) Write(gameNum int, events []game.Event) {
+	for _, event := range events {
+		if l.err != nil {
+			return
+		}
+		event.Game = gameNum
+		l.err = l.encoder.Encode(event)
+	}
+}
+
+// Err returns the first error hit while writing the log
+func (l *EventLog) Err() error {
+	return l.err
+}
+
+// logsGame reports whether a game's events are logged. Sampling is decided
+// from the game seed, so every rotation of a duplicate deal is logged together.
+func (s *Simulator) logsGame(seed int64) bool {
+	if s.eventLog == nil {
+		return false
+	}
+	if s.eventSampleRate >= 1 {
+		return true
+	}
+
+	sample := float64(uint64(DeriveSeed(seed, 1))>>11) / float64(1<<53)
+	return sample < s.eventSampleRate
+}
+
+// recordEvents starts recording a game's events if it is sampled for the event log
+func (s *Simulator) recordEvents(g *game.Game, seed int64) *game.EventRecorder {
+	if !s.logsGame(seed) {
+		return nil
+	}
+
+	recorder := &game.EventRecorder{}
+	g.Events = recorder
+	g.Emit(g.StartEvent(s.persistent))
+	return recorder
+}
+
+// ReadGameEvents reads the events of one game from a JSON Lines event log. A
+// negative game number picks the first game in the log.
+func ReadGameEvents(r io.Reader, gameNum int) ([]game.Event, error) {
+	decoder := json.NewDecoder(r)
+	var events []game.Event
+
+	for {
+		var event game.Event
+		err := decoder.Decode(&event)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading event log: %w", err)
+		}
+
+		if gameNum < 0 {
+			gameNum = event.Game
+		}
+		if event.Game == gameNum {
+			events = append(events, event)
+		} else if len(events) > 0 {
+			// A game's events are written together, so the game is complete
+			break
+		}
+	}
+
+	if len(events) == 0 {
+		return nil, fmt.Errorf("game %d is not in the event log", gameNum)
+	}
+	if events[0].Type != "game_start" {
+		return nil, fmt.Errorf("game %d does not start with a game_start event", gameNum)
+	}
+	return events, nil
+}
+
+// ReplayEvents plays a logged game again from its game_start event, with the
+// given algorithms in their logged seats, and returns the events the engine
+// produces. A deterministic engine reproduces the log exactly.
+func ReplayEvents(start game.Event, algos []game.Algorithm) ([]game.Event, error) {
+	if start.Type != "game_start" || start.Deck == nil || start.Config == nil || start.Dealer == nil {
+		return nil, errors.New("replaying a game needs its game_start event")
+	}
+	if len(algos) != len(start.Algorithms) {
+		return nil, fmt.Errorf("game was played by %d algorithms, got %d", len(start.Algorithms), len(algos))
+	}
+
+	tieBreaker, err := game.TieBreakerByName(start.TieBreaker)
+	if err != nil {
+		return nil, err
+	}
+
+	g := game.NewSeededGame(len(algos), *start.Deck, start.Seed)
+	g.Algorithms = algos
+	g.Dealer = *start.Dealer
+	g.TieBreaker = tieBreaker
+	g.Config = *start.Config
+
+	recorder := &game.EventRecorder{}
+	g.Events = recorder
+	g.Emit(g.StartEvent(start.Persistent))
+	PlayRounds(g, start.Persistent, nil)
+
+	for i := range recorder.Events {
+		recorder.Events[i].Game = start.Game
+	}
+	return recorder.Events, nil
+}
@@ -0,0 +1,264 @@
+package simulator
+
+import (
+	"encoding/json"
+	"flip7-simulator/internal/game"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"sort"
+)
+
+// Default settings for evolving strategies
+const (
+	DefaultPopulation    = 24
+	DefaultGenerations   = 30
+	DefaultFitnessGames  = 200
+	DefaultElite         = 2
+	DefaultMutationRate  = 0.3
+	DefaultMutationScale = 0.5
+	tournamentSize       = 3
+)
+
+// EvolveConfig describes an evolution run. Everything but the workers and
+// the algorithm constructors is saved in each checkpoint, so a resumed run
+// carries on exactly as it was set up.
+type EvolveConfig struct {
+	Weights       int             `json:"weights"` // Length of each genome
+	Population    int             `json:"population"`
+	Generations   int             `json:"generations"`    // Generations to evolve in total, counting those already in a resumed checkpoint
+	Games         int             `json:"games"`          // Games played to measure each genome's fitness
+	Elite         int             `json:"elite"`          // Best genomes carried into the next generation unchanged
+	MutationRate  float64         `json:"mutation_rate"`  // Chance each weight of a child is mutated
+	MutationScale float64         `json:"mutation_scale"` // Standard deviation of a mutation
+	Seed          int64           `json:"seed"`
+	Opponents     []string        `json:"opponents"` // Names of the opponent pool, matching NewOpponents
+	Deck          game.DeckSpec   `json:"deck"`
+	Game          game.GameConfig `json:"game"`
+
+	Workers      int                                    `json:"-"` // Games played in parallel; 0 uses every CPU
+	NewAlgorithm func(weights []float64) game.Algorithm `json:"-"` // Creates the strategy a genome describes
+	NewOpponents []AlgorithmFactory                     `json:"-"` // The fixed opponent pool every genome plays against
+}
+
+// Individual is one genome of an evolving population and its fitness
+type Individual struct {
+	Weights []float64 `json:"weights"`
+	Fitness float64   `json:"fitness"` // Win rate against the opponent pool
+}
+
+// GenerationStats records how a generation fared
+type GenerationStats struct {
+	Generation int     `json:"generation"`
+	Best       float64 `json:"best"`
+	Mean       float64 `json:"mean"`
+}
+
+// EvolveCheckpoint is the state of an evolution run after a generation
+type EvolveCheckpoint struct {
+	Config     EvolveConfig      `json:"config"`
+	Generation int               `json:"generation"` // Generations evaluated so far
+	Population []Individual      `json:"population"` // The latest generation, fittest first
+	History    []GenerationStats `json:"history"`
+}
+
+// Best returns the fittest genome of the latest generation
+func (c *EvolveCheckpoint) Best() Individual {
+	return c.Population[0]
+}
+
+// LoadEvolveCheckpoint reads a checkpoint written by Save
+func LoadEvolveCheckpoint(path string) (*EvolveCheckpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var checkpoint EvolveCheckpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, fmt.Errorf("reading checkpoint from %s: %w", path, err)
+	}
+	if len(checkpoint.Population) == 0 {
+		return nil, fmt.Errorf("reading checkpoint from %s: no population", path)
+	}
+
+	return &checkpoint, nil
+}
+
+// Save writes the checkpoint to a JSON file
+func (c *EvolveCheckpoint) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Evolve runs a genetic algorithm over weight vectors. Each generation every
+// genome plays the same seeded games against the opponent pool and is scored
+// by its win rate; the fittest are kept and the rest of the next generation
+// is bred by tournament selection, uniform crossover and Gaussian mutation.
+// Every shuffle and every random choice is seeded from the config seed and
+// the generation number, so resuming from a checkpoint gives the same result
+// as an uninterrupted run. onGeneration is called with each new checkpoint,
+// and an error from it stops the run.
+func Evolve(config EvolveConfig, resume *EvolveCheckpoint, onGeneration func(*EvolveCheckpoint) error) (*EvolveCheckpoint, error) {
+	config = config.withDefaults()
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
+	checkpoint := resume
+	if checkpoint == nil {
+		rng := rand.New(rand.NewSource(DeriveSeed(config.Seed, 0)))
+		population := make([]Individual, config.Population)
+		for i := range population {
+			population[i].Weights = make([]float64, config.Weights)
+			for j := range population[i].Weights {
+				population[i].Weights[j] = rng.NormFloat64()
+			}
+		}
+
+		checkpoint = &EvolveCheckpoint{Population: population}
+		if err := config.nextGeneration(checkpoint, onGeneration); err != nil {
+			return nil, err
+		}
+	}
+	checkpoint.Config = config
+
+	for checkpoint.Generation < config.Generations {
+		rng := rand.New(rand.NewSource(DeriveSeed(config.Seed, 2*checkpoint.Generation)))
+		checkpoint.Population = config.breed(checkpoint.Population, rng)
+		if err := config.nextGeneration(checkpoint, onGeneration); err != nil {
+			return nil, err
+		}
+	}
+
+	return checkpoint, nil
+}
+
+// withDefaults fills in defaults for unset settings
+func (c EvolveConfig) withDefaults() EvolveConfig {
+	if c.Population <= 0 {
+		c.Population = DefaultPopulation
+	}
+	if c.Generations <= 0 {
+		c.Generations = DefaultGenerations
+	}
+	if c.Games <= 0 {
+		c.Games = DefaultFitnessGames
+	}
+	if c.Elite <= 0 {
+		c.Elite = DefaultElite
+	}
+	if c.MutationRate <= 0 {
+		c.MutationRate = DefaultMutationRate
+	}
+	if c.MutationScale <= 0 {
+		c.MutationScale = DefaultMutationScale
+	}
+	if c.Game == (game.GameConfig{}) {
+		c.Game = game.DefaultGameConfig()
+	}
+	return c
+}
+
+// validate reports settings an evolution run cannot start with
+func (c EvolveConfig) validate() error {
+	if c.Weights <= 0 || c.NewAlgorithm == nil {
+		return fmt.Errorf("need a strategy with at least one weight to evolve")
+	}
+	if len(c.NewOpponents) == 0 {
+		return fmt.Errorf("need at least one opponent to measure fitness against")
+	}
+	if c.Elite >= c.Population {
+		return fmt.Errorf("elite of %d must be smaller than the population of %d", c.Elite, c.Population)
+	}
+	return nil
+}
+
+// nextGeneration measures the fitness of the checkpoint's population as the
+// next generation, ranks it and hands the checkpoint on
+func (c EvolveConfig) nextGeneration(checkpoint *EvolveCheckpoint, onGeneration func(*EvolveCheckpoint) error) error {
+	seed := DeriveSeed(c.Seed, 2*checkpoint.Generation+1)
+
+	total := 0.0
+	for i := range checkpoint.Population {
+		checkpoint.Population[i].Fitness = c.fitness(checkpoint.Population[i].Weights, seed)
+		total += checkpoint.Population[i].Fitness
+	}
+	sort.SliceStable(checkpoint.Population, func(a, b int) bool {
+		return checkpoint.Population[a].Fitness > checkpoint.Population[b].Fitness
+	})
+
+	checkpoint.Generation++
+	checkpoint.Config = c
+	checkpoint.History = append(checkpoint.History, GenerationStats{
+		Generation: checkpoint.Generation,
+		Best:       checkpoint.Population[0].Fitness,
+		Mean:       total / float64(len(checkpoint.Population)),
+	})
+
+	if onGeneration != nil {
+		return onGeneration(checkpoint)
+	}
+	return nil
+}
+
+// fitness returns a genome's win rate over games seeded from seed, seated
+// with the whole opponent pool and taking turns to act first
+func (c EvolveConfig) fitness(weights []float64, seed int64) float64 {
+	factories := append([]AlgorithmFactory{func() game.Algorithm {
+		return c.NewAlgorithm(weights)
+	}}, c.NewOpponents...)
+
+	sim := NewSimulator(factories, Config{
+		NumGames: c.Games,
+		Deck:     c.Deck,
+		SeatMode: "cycle",
+		Game:     c.Game,
+		Seed:     seed,
+		Workers:  c.Workers,
+		Output:   io.Discard,
+	})
+	return sim.simulate()[0].WinRate
+}
+
+// breed makes the next generation from a ranked population: the elite carry
+// over unchanged and every other child has two parents picked by tournament
+func (c EvolveConfig) breed(population []Individual, rng *rand.Rand) []Individual {
+	next := make([]Individual, 0, len(population))
+	for _, elite := range population[:c.Elite] {
+		next = append(next, Individual{Weights: append([]float64(nil), elite.Weights...)})
+	}
+
+	for len(next) < len(population) {
+		mother, father := c.pick(population, rng), c.pick(population, rng)
+
+		weights := make([]float64, c.Weights)
+		for i := range weights {
+			weights[i] = mother.Weights[i]
+			if rng.Intn(2) == 0 {
+				weights[i] = father.Weights[i]
+			}
+			if rng.Float64() < c.MutationRate {
+				weights[i] += rng.NormFloat64() * c.MutationScale
+			}
+		}
+		next = append(next, Individual{Weights: weights})
+	}
+
+	return next
+}
+
+// pick returns the fittest of a few genomes drawn at random
+func (c EvolveConfig) pick(population []Individual, rng *rand.Rand) Individual {
+	best := population[rng.Intn(len(population))]
+	for i := 1; i < tournamentSize; i++ {
+		if contender := population[rng.Intn(len(population))]; contender.Fitness > best.Fitness {
+			best = contender
+		}
+	}
+	return best
+}
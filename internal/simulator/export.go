@@ -0,0 +1,115 @@
+package simulator
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flip7-simulator/internal/game"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// RunConfig records the settings of a finished run
+type RunConfig struct {
+	NumGames       int             `json:"games"`
+	Seed           int64           `json:"seed"`
+	Game           game.GameConfig `json:"game"`
+	Deck           game.DeckSpec   `json:"deck"`
+	DeckSize       int             `json:"deck_size"`
+	PersistentDeck bool            `json:"persistent_deck"`
+	SeatMode       string          `json:"seat_mode"`
+	Duplicate      bool            `json:"duplicate"`
+	TieBreaker     string          `json:"tie_breaker"`
+}
+
+// RunResults holds everything a run measured, for display or export
+type RunResults struct {
+	Config        RunConfig          `json:"config"`
+	Algorithms    []SimulationResult `json:"algorithms"` // Ranked by wins
+	AverageRounds float64            `json:"average_rounds"`
+	TiedGames     int                `json:"tied_games"`  // Games where several players finished level on the highest score
+	SharedWins    int                `json:"shared_wins"` // Games that ended with more than one winner
+	Ratings       *Ratings           `json:"ratings,omitempty"`
+}
+
+// runResults gathers the run's settings and totals alongside the per-algorithm results
+func (s *Simulator) runResults(results []SimulationResult) RunResults {
+	run := RunResults{
+		Config: RunConfig{
+			NumGames:       s.numGames,
+			Seed:           s.seed,
+			Game:           s.gameConfig,
+			Deck:           s.deck,
+			DeckSize:       s.deck.Size(),
+			PersistentDeck: s.persistent,
+			SeatMode:       s.seatMode,
+			Duplicate:      s.duplicate,
+			TieBreaker:     s.tieBreaker.GetName(),
+		},
+		Algorithms: results,
+		TiedGames:  s.tiedGames,
+		SharedWins: s.sharedWins,
+		Ratings:    s.ratings,
+	}
+	if s.numGames > 0 {
+		run.AverageRounds = float64(s.rounds) / float64(s.numGames)
+	}
+	return run
+}
+
+// WriteJSON writes the results of a run as indented JSON
+func WriteJSON(w io.Writer, results RunResults) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(results)
+}
+
+// WriteCSV writes the results of a run as CSV, one row per algorithm with its
+// seat breakdown in seat_N_games and seat_N_wins columns
+func WriteCSV(w io.Writer, results RunResults) error {
+	numSeats := len(results.Algorithms)
+
+	header := []string{
+		"algorithm", "index", "games", "seed", "games_won", "win_rate", "win_rate_low", "win_rate_high",
+		"average_score", "score_stderr", "flip7s", "busts",
+	}
+	for seat := 1; seat <= numSeats; seat++ {
+		header = append(header, fmt.Sprintf("seat_%d_games", seat), fmt.Sprintf("seat_%d_wins", seat))
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, result := range results.Algorithms {
+		row := []string{
+			result.AlgorithmName,
+			strconv.Itoa(result.Index),
+			strconv.Itoa(results.Config.NumGames),
+			strconv.FormatInt(results.Config.Seed, 10),
+			strconv.Itoa(result.GamesWon),
+			formatFloat(result.WinRate),
+			formatFloat(result.WinRateLow),
+			formatFloat(result.WinRateHigh),
+			formatFloat(result.AverageScore),
+			formatFloat(result.ScoreStdErr),
+			strconv.Itoa(result.Flip7Count),
+			strconv.Itoa(result.BustCount),
+		}
+		for seat := 0; seat < numSeats; seat++ {
+			row = append(row, strconv.Itoa(result.SeatGames[seat]), strconv.Itoa(result.SeatWins[seat]))
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// formatFloat formats a value for CSV without losing precision
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
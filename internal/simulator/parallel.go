@@ -0,0 +1,64 @@
+package simulator
+
+import "sync"
+
+// indexedOutcome is a game outcome tagged with its game number
+type indexedOutcome struct {
+	gameNum int
+	outcome gameOutcome
+}
+
+// runGames plays games first to first+count-1 on a pool of workers and hands
+// each outcome to record in game number order. Every worker has its own algorithm instances, and
+// every game its own RNG seeded from the game number, so a seeded run gives the
+// same results no matter how many workers are used.
+func (s *Simulator) runGames(first, count int, record func(gameNum int, outcome gameOutcome)) {
+	workers := s.workers
+	if workers > count {
+		workers = count
+	}
+
+	jobs := make(chan int, workers)
+	outcomes := make(chan indexedOutcome, workers)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		algos := s.newAlgorithms()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for gameNum := range jobs {
+				outcomes <- indexedOutcome{gameNum: gameNum, outcome: s.playGame(gameNum, algos, nil)}
+			}
+		}()
+	}
+
+	go func() {
+		for gameNum := first; gameNum < first+count; gameNum++ {
+			jobs <- gameNum
+		}
+		close(jobs)
+		wg.Wait()
+		close(outcomes)
+	}()
+
+	// Games finish out of order; hold each outcome until every earlier game is recorded
+	waiting := make(map[int]gameOutcome)
+	next := first
+	for result := range outcomes {
+		waiting[result.gameNum] = result.outcome
+		for {
+			outcome, ok := waiting[next]
+			if !ok {
+				break
+			}
+			delete(waiting, next)
+			if outcome.events != nil {
+				s.eventLog.Write(next, outcome.events)
+				outcome.events = nil
+			}
+			record(next, outcome)
+			next++
+		}
+	}
+}
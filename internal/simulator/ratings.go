@@ -0,0 +1,212 @@
+package simulator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"sort"
+)
+
+// Default settings for a new ratings ladder
+const (
+	DefaultInitialRating = 1500
+	DefaultRatingK       = 16
+)
+
+// Rating is one algorithm's place on a ratings ladder
+type Rating struct {
+	Rating float64 `json:"rating"`
+	Games  int     `json:"games"`
+}
+
+// Ratings is a multiplayer Elo ladder. After every game each pair of players
+// is scored like a two-player Elo game, 1 for finishing ahead and 0.5 for
+// finishing level, with K split across the other players at the table.
+type Ratings struct {
+	Initial float64            `json:"initial"`
+	K       float64            `json:"k"`
+	Players map[string]*Rating `json:"players"`
+	frozen  map[string]bool    // Players whose ratings stay fixed while others are rated against them
+}
+
+// NewRatings creates an empty ladder with the default settings
+func NewRatings() *Ratings {
+	return &Ratings{
+		Initial: DefaultInitialRating,
+		K:       DefaultRatingK,
+		Players: make(map[string]*Rating),
+		frozen:  make(map[string]bool),
+	}
+}
+
+// LoadRatings reads a ladder from a JSON file, or returns an empty ladder if the file does not exist
+func LoadRatings(path string) (*Ratings, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return NewRatings(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ratings := NewRatings()
+	if err := json.Unmarshal(data, ratings); err != nil {
+		return nil, fmt.Errorf("reading ratings from %s: %w", path, err)
+	}
+	if ratings.Players == nil {
+		ratings.Players = make(map[string]*Rating)
+	}
+
+	return ratings, nil
+}
+
+// Save writes the ladder to a JSON file
+func (r *Ratings) Save(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Freeze fixes the ratings of everyone currently on the ladder, so new
+// algorithms can be rated against them without moving the existing ladder
+func (r *Ratings) Freeze() {
+	for name := range r.Players {
+		r.frozen[name] = true
+	}
+}
+
+// IsFrozen reports whether a player's rating is fixed
+func (r *Ratings) IsFrozen(name string) bool {
+	return r.frozen[name]
+}
+
+// Get returns a player's rating, adding them to the ladder at the initial rating if they are new
+func (r *Ratings) Get(name string) *Rating {
+	rating, ok := r.Players[name]
+	if !ok {
+		rating = &Rating{Rating: r.Initial}
+		r.Players[name] = rating
+	}
+	return rating
+}
+
+// Update rates one game. names holds each player's algorithm and ranks their
+// finishing position (0 = first); equal ranks finished level.
+func (r *Ratings) Update(names []string, ranks []int) {
+	if len(names) < 2 {
+		return
+	}
+
+	ratings := make([]*Rating, len(names))
+	for i, name := range names {
+		ratings[i] = r.Get(name)
+	}
+
+	// Every pair is rated on the ratings from before the game
+	k := r.K / float64(len(names)-1)
+	deltas := make([]float64, len(names))
+	for i := range names {
+		for j := range names {
+			if i == j {
+				continue
+			}
+
+			actual := 0.5
+			if ranks[i] < ranks[j] {
+				actual = 1
+			} else if ranks[i] > ranks[j] {
+				actual = 0
+			}
+			expected := ExpectedScore(ratings[i].Rating - ratings[j].Rating)
+			deltas[i] += k * (actual - expected)
+		}
+	}
+
+	for i, name := range names {
+		ratings[i].Games++
+		if !r.frozen[name] {
+			ratings[i].Rating += deltas[i]
+		}
+	}
+}
+
+// Ranked returns the names on the ladder from highest to lowest rating
+func (r *Ratings) Ranked() []string {
+	names := make([]string, 0, len(r.Players))
+	for name := range r.Players {
+		names = append(names, name)
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		a, b := r.Players[names[i]].Rating, r.Players[names[j]].Rating
+		if a != b {
+			return a > b
+		}
+		return names[i] < names[j]
+	})
+
+	return names
+}
+
+// rateGame updates the ratings from a game's finishing order, if the run keeps ratings
+func (s *Simulator) rateGame(outcome gameOutcome) {
+	if s.ratings == nil {
+		return
+	}
+
+	names := make([]string, len(s.algorithms))
+	for i, algo := range s.algorithms {
+		names[i] = algo.GetName()
+	}
+
+	s.ratings.Update(names, finishingRanks(outcome))
+}
+
+// finishingRanks returns each player's finishing position in a game: the number of players who finished ahead of them
+func finishingRanks(outcome gameOutcome) []int {
+	won := make([]bool, len(outcome.scores))
+	for _, winner := range outcome.winners {
+		won[winner] = true
+	}
+
+	ranks := make([]int, len(outcome.scores))
+	for a := range ranks {
+		for b := range ranks {
+			if a != b && finishedAhead(outcome, won, b, a) == 1 {
+				ranks[a]++
+			}
+		}
+	}
+	return ranks
+}
+
+// displayRatings shows the ratings ladder
+func (s *Simulator) displayRatings() {
+	if s.ratings == nil {
+		return
+	}
+
+	fmt.Fprintf(s.out, "\n=== Ratings (multiplayer Elo, K=%g) ===\n", s.ratings.K)
+	fmt.Fprintf(s.out, "%-20s %8s %8s\n", "Algorithm", "Rating", "Games")
+	fmt.Fprintf(s.out, "%-20s %8s %8s\n", "=========", "======", "=====")
+
+	frozen := false
+	for _, name := range s.ratings.Ranked() {
+		rating := s.ratings.Players[name]
+		marker := ""
+		if s.ratings.IsFrozen(name) {
+			marker = "  *"
+			frozen = true
+		}
+		fmt.Fprintf(s.out, "%-20s %8.0f %8d%s\n", name, math.Round(rating.Rating), rating.Games, marker)
+	}
+
+	if frozen {
+		fmt.Fprintf(s.out, "\n* rating held fixed during this run\n")
+	}
+}
@@ -0,0 +1,17 @@
+package simulator
+
+import "time"
+
+// DeriveSeed returns the seed for one game of a run from the run's master seed.
+// It uses the SplitMix64 mixer so neighbouring game indexes get unrelated seeds.
+func DeriveSeed(master int64, index int) int64 {
+	z := uint64(master) + uint64(index+1)*0x9E3779B97F4A7C15
+	z = (z ^ (z >> 30)) * 0xBF58476D1CE4E5B9
+	z = (z ^ (z >> 27)) * 0x94D049BB133111EB
+	return int64(z ^ (z >> 31))
+}
+
+// clockSeed returns a master seed for runs that were not given one
+func clockSeed() int64 {
+	return time.Now().UnixNano()
+}
@@ -3,151 +3,425 @@ package simulator
 import (
 	"flip7-simulator/internal/game"
 	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sort"
 )
 
 // SimulationResult holds the results for an algorithm
 type SimulationResult struct {
-	AlgorithmName string
-	GamesWon      int
-	TotalScore    int
-	AverageScore  float64
-	Flip7Count    int
-	BustCount     int
+	AlgorithmName string    `json:"algorithm"`
+	Index         int       `json:"index"` // Position of the algorithm in the run
+	GamesWon      int       `json:"games_won"`
+	WinRate       float64   `json:"win_rate"`
+	WinRateLow    float64   `json:"win_rate_low"` // 95% Wilson confidence interval on the win rate
+	WinRateHigh   float64   `json:"win_rate_high"`
+	TotalScore    int       `json:"total_score"`
+	AverageScore  float64   `json:"average_score"`
+	ScoreStdErr   float64   `json:"score_stderr"` // Standard error of the average score
+	Flip7Count    int       `json:"flip7s"`
+	BustCount     int       `json:"busts"`
+	SeatGames     []int     `json:"seat_games"` // Games played from each starting seat (0 = first to act)
+	SeatWins      []int     `json:"seat_wins"`  // Games won from each starting seat
+	WinsOver      []int     `json:"wins_over"`  // Games won that each other algorithm (by Index) did not win
+	PValues       []float64 `json:"p_values"`   // Sign test p-value against each other algorithm (by Index)
+	scoreSquares  float64
 }
 
+// Config holds the settings for a simulation run
+type Config struct {
+	NumGames       int
+	Deck           game.DeckSpec
+	PersistentDeck bool            // Carry the deck and discard pile across rounds instead of rebuilding each round
+	SeatMode       string          // How the first dealer is picked each game: "fixed", "cycle" or "random"
+	TieBreaker     game.TieBreaker // Settles ties for the highest score; defaults to a shared win
+	Game           game.GameConfig // Target score and round limits; defaults to the race to 200
+	Seed           int64           // Master seed for the run; 0 picks one from the clock
+	Workers        int             // Games played in parallel; 0 uses every CPU
+	Duplicate      bool            // Replay every deal with the algorithms rotated through every seat; not with PersistentDeck
+	Output         io.Writer       // Where progress and results are printed; defaults to stdout
+	EventLog       *EventLog       // Log of every sampled game's events; nil logs nothing
+	EventSample    float64         // Fraction of games written to the event log; 0 logs every game
+	Ratings        *Ratings        // Ladder updated from every game's finishing order; nil keeps no ratings
+}
+
+// AlgorithmFactory creates a fresh instance of an algorithm
+type AlgorithmFactory = func() game.Algorithm
+
 // Simulator runs multiple games with different algorithms
 type Simulator struct {
-	algorithms []game.Algorithm
-	numGames   int
+	factories       []AlgorithmFactory
+	algorithms      []game.Algorithm // Instances used for names and single-game replays
+	workers         int
+	numGames        int
+	deck            game.DeckSpec
+	persistent      bool
+	duplicate       bool
+	seatMode        string
+	tieBreaker      game.TieBreaker
+	gameConfig      game.GameConfig
+	seed            int64
+	ratings         *Ratings
+	out             io.Writer
+	eventLog        *EventLog
+	eventSampleRate float64
+	tiedGames       int // Games where several players finished level on the highest score
+	sharedWins      int // Games that ended with more than one winner
+	rounds          int // Rounds played across all games
+}
+
+// gameOutcome holds the result of a single game, indexed by player
+type gameOutcome struct {
+	winners     []int
+	tied        bool
+	rounds      int
+	scores      []int
+	flip7s      []int
+	busts       []int
+	events      []game.Event // Events of a game sampled for the event log
+	firstDealer int
+	players     []int // Player ID each algorithm was seated as
+	seed        int64
+}
+
+// NewSimulator creates a new simulator. Each factory creates one seat's
+// algorithm; every worker gets its own instances so algorithms may keep state.
+func NewSimulator(factories []AlgorithmFactory, config Config) *Simulator {
+	seatMode := config.SeatMode
+	if seatMode == "" {
+		seatMode = "fixed"
+	}
+
+	tieBreaker := config.TieBreaker
+	if tieBreaker == nil {
+		tieBreaker = game.SharedWinTieBreaker{}
+	}
+
+	seed := config.Seed
+	if seed == 0 {
+		seed = clockSeed()
+	}
+
+	gameConfig := config.Game
+	if gameConfig == (game.GameConfig{}) {
+		gameConfig = game.DefaultGameConfig()
+	}
+
+	out := config.Output
+	if out == nil {
+		out = os.Stdout
+	}
+
+	eventSample := 1.0
+	if config.EventSample > 0 && config.EventSample < 1 {
+		eventSample = config.EventSample
+	}
+
+	workers := config.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	numGames := config.NumGames
+	if config.Duplicate {
+		numGames = wholeDeals(numGames, len(factories))
+	}
+
+	s := &Simulator{
+		factories:       factories,
+		numGames:        numGames,
+		deck:            config.Deck,
+		persistent:      config.PersistentDeck,
+		duplicate:       config.Duplicate,
+		seatMode:        seatMode,
+		tieBreaker:      tieBreaker,
+		gameConfig:      gameConfig,
+		seed:            seed,
+		ratings:         config.Ratings,
+		out:             out,
+		eventLog:        config.EventLog,
+		eventSampleRate: eventSample,
+		workers:         workers,
+	}
+	s.algorithms = s.newAlgorithms()
+
+	return s
 }
 
-// NewSimulator creates a new simulator
-func NewSimulator(algorithms []game.Algorithm, numGames int) *Simulator {
-	return &Simulator{
-		algorithms: algorithms,
-		numGames:   numGames,
+// newAlgorithms creates a fresh set of algorithm instances, one per seat
+func (s *Simulator) newAlgorithms() []game.Algorithm {
+	algos := make([]game.Algorithm, len(s.factories))
+	for i, factory := range s.factories {
+		algos[i] = factory()
 	}
+	return algos
+}
+
+// Run executes the simulation, shows the results and returns them, ranked by wins
+func (s *Simulator) Run() RunResults {
+	results := s.simulate()
+	s.displayResults(results)
+	return s.runResults(results)
 }
 
-// Run executes the simulation
-func (s *Simulator) Run() {
-	results := make([]SimulationResult, len(s.algorithms))
+// simulate plays every game of the run and totals the results per algorithm
+func (s *Simulator) simulate() []SimulationResult {
+	numPlayers := len(s.algorithms)
+	results := make([]SimulationResult, numPlayers)
 
 	// Initialize results
 	for i, algo := range s.algorithms {
 		results[i] = SimulationResult{
 			AlgorithmName: algo.GetName(),
+			Index:         i,
+			SeatGames:     make([]int, numPlayers),
+			SeatWins:      make([]int, numPlayers),
+			WinsOver:      make([]int, numPlayers),
 		}
 	}
 
-	// Run games
-	for gameNum := 0; gameNum < s.numGames; gameNum++ {
-		winner, scores, flip7s, busts := s.playGame()
+	progressEvery := s.numGames / 10
+	if progressEvery < 100 {
+		progressEvery = 100
+	}
+
+	// Run games, totalling outcomes in game order
+	s.tiedGames, s.sharedWins, s.rounds = 0, 0, 0
+
+	s.runGames(0, s.numGames, func(gameNum int, outcome gameOutcome) {
+		won := make([]bool, numPlayers)
+		for _, winner := range outcome.winners {
+			won[winner] = true
+		}
 
 		// Update results
 		for i := range results {
-			results[i].TotalScore += scores[i]
-			results[i].Flip7Count += flip7s[i]
-			results[i].BustCount += busts[i]
+			results[i].TotalScore += outcome.scores[i]
+			results[i].scoreSquares += float64(outcome.scores[i]) * float64(outcome.scores[i])
+			results[i].Flip7Count += outcome.flip7s[i]
+			results[i].BustCount += outcome.busts[i]
 
-			if winner == i {
+			seat := startingSeat(outcome.players[i], outcome.firstDealer, numPlayers)
+			results[i].SeatGames[seat]++
+
+			if won[i] {
 				results[i].GamesWon++
+				results[i].SeatWins[seat]++
+
+				for j := range results {
+					if !won[j] {
+						results[i].WinsOver[j]++
+					}
+				}
 			}
 		}
 
+		s.rounds += outcome.rounds
+		s.rateGame(outcome)
+		if outcome.tied {
+			s.tiedGames++
+		}
+		if len(outcome.winners) > 1 {
+			s.sharedWins++
+		}
+
 		// Progress indicator
-		if (gameNum+1)%100 == 0 {
-			fmt.Printf("Completed %d/%d games\n", gameNum+1, s.numGames)
+		if (gameNum+1)%progressEvery == 0 {
+			fmt.Fprintf(s.out, "Completed %d/%d games\n", gameNum+1, s.numGames)
 		}
-	}
+	})
 
-	// Calculate averages
+	// Calculate averages and their uncertainty
 	for i := range results {
 		if s.numGames > 0 {
 			results[i].AverageScore = float64(results[i].TotalScore) / float64(s.numGames)
 		}
+		if s.numGames > 0 {
+			results[i].WinRate = float64(results[i].GamesWon) / float64(s.numGames)
+		}
+		results[i].WinRateLow, results[i].WinRateHigh = WilsonInterval(results[i].GamesWon, s.numGames, z95)
+		results[i].ScoreStdErr = StandardError(float64(results[i].TotalScore), results[i].scoreSquares, s.numGames)
 	}
 
-	s.displayResults(results)
+	// Compare every pair of algorithms
+	for i := range results {
+		results[i].PValues = make([]float64, numPlayers)
+		for j := range results {
+			results[i].PValues[j] = s.pValue(results[i], results[j])
+		}
+	}
+
+	return results
 }
 
-// playGame runs a single game and returns winner index, scores, flip7 counts, and bust counts
-func (s *Simulator) playGame() (int, []int, []int, []int) {
-	g := game.NewGame(len(s.algorithms))
-	scores := make([]int, len(s.algorithms))
-	flip7s := make([]int, len(s.algorithms))
-	busts := make([]int, len(s.algorithms))
+// NumGames returns the number of games in the run, rounded up to whole deals in duplicate mode
+func (s *Simulator) NumGames() int {
+	return s.numGames
+}
 
-	// Play until someone reaches 200 points
-	for {
-		g.CreateDeck()
-		g.StartNewRound()
+// Seed returns the master seed of the run
+func (s *Simulator) Seed() int64 {
+	return s.seed
+}
 
-		// Deal initial cards
-		g.DealInitialCard()
+// ReplayGame plays a single game of the run on its own, printing the state after every round
+func (s *Simulator) ReplayGame(gameNum int) {
+	outcome := s.playGame(gameNum, s.algorithms, func(g *game.Game) {
+		g.WriteGameState(s.out)
+		for _, player := range g.Players {
+			fmt.Fprintf(s.out, "Player %d (%s): %d points\n", player.ID, g.Algorithms[player.ID].GetName(), player.GameScore)
+		}
+		fmt.Fprintf(s.out, "\n")
+	})
 
-		// Play the round
-		for !g.IsRoundOver() {
-			for playerID := 0; playerID < len(s.algorithms); playerID++ {
-				player := g.Players[playerID]
+	fmt.Fprintf(s.out, "Game %d (seed %d) finished after %d rounds\n", gameNum, outcome.seed, outcome.rounds)
+	for _, winner := range outcome.winners {
+		fmt.Fprintf(s.out, "Winner: Player %d (%s) with %d points\n", outcome.players[winner], s.algorithms[winner].GetName(), outcome.scores[winner])
+	}
+}
 
-				if player.IsBust || player.HasStood {
-					continue
-				}
+// firstDealer picks who deals the opening round of a game according to the seat mode
+func (s *Simulator) firstDealer(gameNum int, seed int64) int {
+	numPlayers := len(s.algorithms)
 
-				// Get algorithm decision
-				gameState := g.GetGameState()
-				cardsRemaining := g.GetCardsRemaining()
-				decision := s.algorithms[playerID].MakeDecision(player, gameState, cardsRemaining)
+	switch s.seatMode {
+	case "cycle":
+		return (numPlayers - 1 + gameNum) % numPlayers
+	case "random":
+		// Derived from the game seed so the game can be replayed on its own
+		return int(uint64(DeriveSeed(seed, 0)) % uint64(numPlayers))
+	default:
+		// Player 0 always acts first
+		return numPlayers - 1
+	}
+}
 
-				if decision.Action == "hit" {
-					g.PlayerHit(playerID)
-				} else {
-					g.PlayerStand(playerID)
-				}
+// deal returns which deal a game plays and how far the algorithms are rotated
+// round the table. Outside duplicate mode every game is its own deal.
+func (s *Simulator) deal(gameNum int) (int, int) {
+	if !s.duplicate {
+		return gameNum, 0
+	}
+	numPlayers := len(s.algorithms)
+	return gameNum / numPlayers, gameNum % numPlayers
+}
 
-				// Check if round is over due to Flip 7
-				if g.IsRoundOver() {
-					break
-				}
-			}
-		}
+// wholeDeals rounds a number of games up so every deal is played from every seat
+func wholeDeals(numGames, numPlayers int) int {
+	if numPlayers == 0 {
+		return numGames
+	}
+	return (numGames + numPlayers - 1) / numPlayers * numPlayers
+}
 
-		// Calculate round scores
-		roundWinner := -1
-		highestScore := -1
+// startingSeat returns a player's position in the opening round's turn order (0 = first to act)
+func startingSeat(playerID, firstDealer, numPlayers int) int {
+	return (playerID - firstDealer - 1 + numPlayers) % numPlayers
+}
 
-		for playerID := 0; playerID < len(s.algorithms); playerID++ {
-			roundScore := g.CalculateScore(playerID)
-			g.Players[playerID].GameScore += roundScore
-			scores[playerID] = g.Players[playerID].GameScore
+// playGame runs one game of the run with the given algorithm instances, seeded
+// from the master seed and the game's deal. onRound, if set, is called after
+// every round is scored. The outcome is indexed by algorithm, not player ID.
+func (s *Simulator) playGame(gameNum int, algos []game.Algorithm, onRound func(g *game.Game)) gameOutcome {
+	numPlayers := len(s.algorithms)
+	deal, rotation := s.deal(gameNum)
+	seed := DeriveSeed(s.seed, deal)
+	firstDealer := s.firstDealer(deal, seed)
 
-			if g.HasFlip7(playerID) {
-				flip7s[playerID]++
-			}
+	// Algorithm i plays as player (i + rotation) mod n
+	players := make([]int, numPlayers)
+	seated := make([]game.Algorithm, numPlayers)
+	for i, algo := range algos {
+		players[i] = (i + rotation) % numPlayers
+		seated[players[i]] = algo
+	}
+
+	g := game.NewSeededGame(numPlayers, s.deck, seed)
+	g.Algorithms = seated
+	g.Dealer = firstDealer
+	g.TieBreaker = s.tieBreaker
+	g.Config = s.gameConfig
+	recorder := s.recordEvents(g, seed)
+
+	outcome := gameOutcome{
+		scores:      make([]int, numPlayers),
+		flip7s:      make([]int, numPlayers),
+		busts:       make([]int, numPlayers),
+		firstDealer: firstDealer,
+		players:     players,
+		seed:        seed,
+	}
+
+	PlayRounds(g, s.persistent, func(g *game.Game) {
+		for i, playerID := range players {
+			outcome.scores[i] = g.Players[playerID].GameScore
+			outcome.flip7s[i] = g.Players[playerID].Flip7s
 
 			if g.Players[playerID].IsBust {
-				busts[playerID]++
+				outcome.busts[i]++
 			}
+		}
 
-			// Track highest score for potential game end
-			if scores[playerID] > highestScore {
-				highestScore = scores[playerID]
-				roundWinner = playerID
-			}
+		if onRound != nil {
+			onRound(g)
+		}
+	})
+
+	for _, winner := range g.Winners {
+		outcome.winners = append(outcome.winners, (winner-rotation+numPlayers)%numPlayers)
+	}
+	sort.Ints(outcome.winners)
+	if recorder != nil {
+		outcome.events = recorder.Events
+	}
+	outcome.tied = g.TiedFinish
+	outcome.rounds = g.CurrentRound
+	return outcome
+}
+
+// PlayRounds plays a game that has been set up until the game config's end
+// condition is met, calling onRound (if set) after every round is scored
+func PlayRounds(g *game.Game, persistent bool, onRound func(g *game.Game)) {
+	// A persistent deck is built once; otherwise every round gets a fresh deck
+	if persistent {
+		g.CreateDeck()
+	}
+
+	for {
+		g.StartNewRound()
+		if !persistent {
+			g.CreateDeck()
+		}
+
+		// Deal and play the round, starting left of the dealer
+		g.PlayRound()
+
+		// Calculate round scores
+		g.ScoreRound()
+		if onRound != nil {
+			onRound(g)
 		}
 
-		// Check if game is over (someone reached 200)
-		if highestScore >= 200 {
-			return roundWinner, scores, flip7s, busts
+		if g.CheckGameOver() {
+			return
 		}
 	}
 }
 
 // displayResults shows the simulation results
 func (s *Simulator) displayResults(results []SimulationResult) {
-	fmt.Printf("\n=== Flip 7 Simulation Results ===\n")
-	fmt.Printf("Total Games: %d\n\n", s.numGames)
+	fmt.Fprintf(s.out, "\n=== Flip 7 Simulation Results ===\n")
+	fmt.Fprintf(s.out, "Total Games: %d\n", s.numGames)
+	fmt.Fprintf(s.out, "Seed: %d\n", s.seed)
+	fmt.Fprintf(s.out, "Game: %s\n", s.gameConfig)
+	fmt.Fprintf(s.out, "Deck: %s (%d cards)", s.deck.Name, s.deck.Size())
+	if s.persistent {
+		fmt.Fprintf(s.out, ", persistent across rounds")
+	}
+	fmt.Fprintf(s.out, "\n")
+	fmt.Fprintf(s.out, "Seating: %s\n", s.seatingDescription())
+	fmt.Fprintf(s.out, "Tie-breaker: %s\n\n", s.tieBreaker.GetName())
 
 	// Sort by win rate
 	for i := 0; i < len(results)-1; i++ {
@@ -158,19 +432,122 @@ func (s *Simulator) displayResults(results []SimulationResult) {
 		}
 	}
 
-	fmt.Printf("%-20s %8s %8s %12s %8s %8s\n", "Algorithm", "Wins", "Win%", "Avg Score", "Flip 7s", "Busts")
-	fmt.Printf("%-20s %8s %8s %12s %8s %8s\n", "=========", "====", "====", "=========", "=======", "=====")
+	fmt.Fprintf(s.out, "%-20s %8s %8s %15s %12s %7s %8s %8s\n", "Algorithm", "Wins", "Win%", "95% CI", "Avg Score", "±SE", "Flip 7s", "Busts")
+	fmt.Fprintf(s.out, "%-20s %8s %8s %15s %12s %7s %8s %8s\n", "=========", "====", "====", "======", "=========", "===", "=======", "=====")
 
-	for _, result := range results {
+	indistinguishable := false
+	for i, result := range results {
 		winRate := float64(result.GamesWon) / float64(s.numGames) * 100
-		fmt.Printf("%-20s %8d %7.1f%% %11.1f %8d %8d\n",
+
+		// Flag rankings that could be random swings against the algorithm ranked just above
+		marker := ""
+		if i > 0 && s.pValue(results[i-1], result) >= significanceLevel {
+			marker = "  ≈"
+			indistinguishable = true
+		}
+
+		fmt.Fprintf(s.out, "%-20s %8d %7.1f%% %15s %11.1f %7.1f %8d %8d%s\n",
 			result.AlgorithmName,
 			result.GamesWon,
 			winRate,
+			fmt.Sprintf("%.1f-%.1f%%", result.WinRateLow*100, result.WinRateHigh*100),
 			result.AverageScore,
+			result.ScoreStdErr,
 			result.Flip7Count,
-			result.BustCount)
+			result.BustCount,
+			marker)
 	}
 
-	fmt.Printf("\n")
+	if indistinguishable {
+		fmt.Fprintf(s.out, "\n≈ statistically indistinguishable from the algorithm above (p >= %.2f)\n", significanceLevel)
+	}
+
+	s.displayPairwise(results)
+
+	if s.numGames > 0 {
+		fmt.Fprintf(s.out, "\nAverage rounds per game: %.1f\n", float64(s.rounds)/float64(s.numGames))
+		fmt.Fprintf(s.out, "Tied finishes: %d (%.1f%%), shared wins: %d (%.1f%%)\n",
+			s.tiedGames,
+			float64(s.tiedGames)/float64(s.numGames)*100,
+			s.sharedWins,
+			float64(s.sharedWins)/float64(s.numGames)*100)
+	}
+
+	s.displaySeatResults(results)
+	s.displayRatings()
+
+	fmt.Fprintf(s.out, "\n")
+}
+
+// seatingDescription describes how players are seated, for result headers
+func (s *Simulator) seatingDescription() string {
+	if s.duplicate {
+		return s.seatMode + ", duplicate deals (every deal played from every seat)"
+	}
+	return s.seatMode
+}
+
+// pValue returns the sign test p-value that two algorithms are equally strong
+func (s *Simulator) pValue(a, b SimulationResult) float64 {
+	return SignTestPValue(a.WinsOver[b.Index], b.WinsOver[a.Index])
+}
+
+// displayPairwise shows the p-value of the pairwise significance test between every two algorithms
+func (s *Simulator) displayPairwise(results []SimulationResult) {
+	fmt.Fprintf(s.out, "\n=== Pairwise Significance (p-values, sign test on games only one of the pair won) ===\n")
+	fmt.Fprintf(s.out, "%-20s", "Algorithm")
+	for j := range results {
+		fmt.Fprintf(s.out, " %7s", fmt.Sprintf("#%d", j+1))
+	}
+	fmt.Fprintf(s.out, "\n")
+
+	for i, a := range results {
+		fmt.Fprintf(s.out, "%-20s", fmt.Sprintf("#%d %s", i+1, a.AlgorithmName))
+		for j, b := range results {
+			if i == j {
+				fmt.Fprintf(s.out, " %7s", "-")
+				continue
+			}
+			fmt.Fprintf(s.out, " %7.3f", s.pValue(a, b))
+		}
+		fmt.Fprintf(s.out, "\n")
+	}
+}
+
+// displaySeatResults shows win rates split by starting seat (seat 1 acts first in the opening round)
+func (s *Simulator) displaySeatResults(results []SimulationResult) {
+	numSeats := len(s.algorithms)
+	seatGames := make([]int, numSeats)
+	seatWins := make([]int, numSeats)
+
+	fmt.Fprintf(s.out, "\n=== Win%% by Starting Seat ===\n")
+	fmt.Fprintf(s.out, "%-20s", "Algorithm")
+	for seat := 0; seat < numSeats; seat++ {
+		fmt.Fprintf(s.out, " %7s", fmt.Sprintf("Seat %d", seat+1))
+	}
+	fmt.Fprintf(s.out, "\n")
+
+	for _, result := range results {
+		fmt.Fprintf(s.out, "%-20s", result.AlgorithmName)
+		for seat := 0; seat < numSeats; seat++ {
+			seatGames[seat] += result.SeatGames[seat]
+			seatWins[seat] += result.SeatWins[seat]
+			fmt.Fprintf(s.out, " %7s", formatWinRate(result.SeatWins[seat], result.SeatGames[seat]))
+		}
+		fmt.Fprintf(s.out, "\n")
+	}
+
+	fmt.Fprintf(s.out, "%-20s", "All")
+	for seat := 0; seat < numSeats; seat++ {
+		fmt.Fprintf(s.out, " %7s", formatWinRate(seatWins[seat], seatGames[seat]))
+	}
+	fmt.Fprintf(s.out, "\n")
+}
+
+// formatWinRate formats wins out of games as a percentage, or "-" when no games were played
+func formatWinRate(wins, games int) string {
+	if games == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", float64(wins)/float64(games)*100)
 }
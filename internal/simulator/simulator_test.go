@@ -0,0 +1,592 @@
+package simulator
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"flip7-simulator/internal/algorithms"
+	"flip7-simulator/internal/game"
+	"io"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newTestSimulator(seed int64, workers int) *Simulator {
+	return NewSimulator([]AlgorithmFactory{
+		func() game.Algorithm { return game.FromLegacy(algorithms.NewStopAtScoreAlgorithm(25)) },
+		func() game.Algorithm { return game.FromLegacy(algorithms.NewConservativeAlgorithm()) },
+		func() game.Algorithm { return game.FromLegacy(algorithms.NewAdaptiveAlgorithm()) },
+	}, Config{
+		NumGames: 200,
+		Deck:     game.OfficialDeck(),
+		SeatMode: "random",
+		Seed:     seed,
+		Workers:  workers,
+	})
+}
+
+func TestSameSeedReplaysSameGames(t *testing.T) {
+	first := newTestSimulator(99, 1)
+	second := newTestSimulator(99, 1)
+
+	for gameNum := 0; gameNum < 20; gameNum++ {
+		a := first.playGame(gameNum, first.algorithms, nil)
+		b := second.playGame(gameNum, second.algorithms, nil)
+
+		if !reflect.DeepEqual(a, b) {
+			t.Fatalf("Game %d differs between runs with the same seed", gameNum)
+		}
+	}
+}
+
+func TestReplayGameWritesToOutput(t *testing.T) {
+	sim := newTestSimulator(99, 1)
+	var out bytes.Buffer
+	sim.out = &out
+
+	sim.ReplayGame(3)
+	if !strings.Contains(out.String(), "=== Game State ===") || !strings.Contains(out.String(), "Game 3 (seed") {
+		t.Errorf("Expected every round and the result in the run's output, got %q", out.String())
+	}
+}
+
+func TestDeriveSeedDiffersPerGame(t *testing.T) {
+	seen := make(map[int64]bool)
+	for gameNum := 0; gameNum < 1000; gameNum++ {
+		seed := DeriveSeed(1, gameNum)
+		if seen[seed] {
+			t.Fatalf("Game %d reuses a seed", gameNum)
+		}
+		seen[seed] = true
+	}
+}
+
+func TestResultsIndependentOfWorkerCount(t *testing.T) {
+	serial := newTestSimulator(7, 1)
+	parallel := newTestSimulator(7, 8)
+
+	serialResults := serial.simulate()
+	parallelResults := parallel.simulate()
+
+	if !reflect.DeepEqual(serialResults, parallelResults) {
+		t.Error("A seeded run should give the same results with any number of workers")
+	}
+}
+
+func TestWilsonInterval(t *testing.T) {
+	low, high := WilsonInterval(50, 100, z95)
+	if math.Abs(low-0.4038) > 0.001 || math.Abs(high-0.5962) > 0.001 {
+		t.Errorf("Expected about 0.404-0.596 for 50/100, got %.4f-%.4f", low, high)
+	}
+
+	low, high = WilsonInterval(0, 10, z95)
+	if low != 0 || high <= 0 || high >= 1 {
+		t.Errorf("Interval for 0/10 should start at 0, got %.4f-%.4f", low, high)
+	}
+}
+
+func TestStandardError(t *testing.T) {
+	// Values 2, 4, 4, 4, 5, 5, 7, 9 have sample standard deviation 2.138
+	values := []float64{2, 4, 4, 4, 5, 5, 7, 9}
+	sum, sumSquares := 0.0, 0.0
+	for _, v := range values {
+		sum += v
+		sumSquares += v * v
+	}
+
+	stdErr := StandardError(sum, sumSquares, len(values))
+	if math.Abs(stdErr-2.138/math.Sqrt(8)) > 0.001 {
+		t.Errorf("Expected standard error %.4f, got %.4f", 2.138/math.Sqrt(8), stdErr)
+	}
+}
+
+func TestSignTestPValue(t *testing.T) {
+	if p := SignTestPValue(50, 50); p != 1 {
+		t.Errorf("Equal records should have p-value 1, got %.4f", p)
+	}
+
+	if p := SignTestPValue(80, 20); p >= 0.001 {
+		t.Errorf("80 to 20 should be significant, got p-value %.4f", p)
+	}
+
+	if p := SignTestPValue(0, 0); p != 1 {
+		t.Errorf("No decisive games should have p-value 1, got %.4f", p)
+	}
+}
+
+func TestSPRTBounds(t *testing.T) {
+	lower, upper := SPRTBounds(0.05, 0.05)
+	if math.Abs(lower+2.944) > 0.001 || math.Abs(upper-2.944) > 0.001 {
+		t.Errorf("Expected bounds of about [-2.944, 2.944], got [%.3f, %.3f]", lower, upper)
+	}
+}
+
+func TestLogLikelihoodRatio(t *testing.T) {
+	if llr := LogLikelihoodRatio(0, 0, 0, 0, 20); llr != 0 {
+		t.Errorf("Expected LLR 0 with no games, got %f", llr)
+	}
+
+	// Scoring right at the H1 expectation favours H1, right at H0 favours H0
+	if llr := LogLikelihoodRatio(600, 0, 400, 0, 20); llr <= 0 {
+		t.Errorf("Expected a positive LLR for a 60%% score, got %f", llr)
+	}
+	if llr := LogLikelihoodRatio(500, 0, 500, 0, 20); llr >= 0 {
+		t.Errorf("Expected a negative LLR for a 50%% score, got %f", llr)
+	}
+
+	// Halfway between the hypotheses the evidence is balanced
+	midpoint := (ExpectedScore(0) + ExpectedScore(20)) / 2
+	wins := int(math.Round(midpoint * 100000))
+	if llr := LogLikelihoodRatio(wins, 0, 100000-wins, 0, 20); math.Abs(llr) > 0.5 {
+		t.Errorf("Expected an LLR near 0 halfway between the hypotheses, got %f", llr)
+	}
+
+	// A clean sweep is still evidence, not zero variance
+	lower, upper := SPRTBounds(0.05, 0.05)
+	if llr := LogLikelihoodRatio(30, 0, 0, 0, 20); llr < upper {
+		t.Errorf("Expected 30 straight wins to accept H1, got LLR %f", llr)
+	}
+	if llr := LogLikelihoodRatio(0, 0, 30, 0, 20); llr > lower {
+		t.Errorf("Expected 30 straight losses to accept H0, got LLR %f", llr)
+	}
+}
+
+func TestEloDifference(t *testing.T) {
+	for _, elo := range []float64{-100, 0, 35} {
+		if got := EloDifference(ExpectedScore(elo)); math.Abs(got-elo) > 1e-9 {
+			t.Errorf("Expected Elo %f to round trip, got %f", elo, got)
+		}
+	}
+}
+
+func TestSPRTStopsAtSameGame(t *testing.T) {
+	newSim := func(workers int) *Simulator {
+		return NewSimulator([]AlgorithmFactory{
+			func() game.Algorithm { return game.FromLegacy(algorithms.NewAlwaysHitAlgorithm()) },
+			func() game.Algorithm { return game.FromLegacy(algorithms.NewAdaptiveAlgorithm()) },
+		}, Config{
+			Deck:     game.OfficialDeck(),
+			SeatMode: "cycle",
+			Seed:     5,
+			Workers:  workers,
+		})
+	}
+	config := SPRTConfig{Elo0: 0, Elo1: 50, Alpha: 0.05, Beta: 0.05, MaxGames: 2000, ReportEvery: 50}
+
+	first, err := newSim(1).RunSPRT(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := newSim(4).RunSPRT(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first != second {
+		t.Errorf("Expected the same result regardless of worker count, got %+v and %+v", first, second)
+	}
+	if first.Verdict != "H0" {
+		t.Errorf("Expected Always Hit not to beat Adaptive, got verdict %q after %d games", first.Verdict, first.Games)
+	}
+}
+
+func TestSPRTRejectsBadHypotheses(t *testing.T) {
+	sim := NewSimulator([]AlgorithmFactory{
+		func() game.Algorithm { return game.FromLegacy(algorithms.NewAlwaysHitAlgorithm()) },
+		func() game.Algorithm { return game.FromLegacy(algorithms.NewAdaptiveAlgorithm()) },
+	}, Config{Deck: game.OfficialDeck(), Seed: 5, Output: io.Discard})
+
+	for _, config := range []SPRTConfig{
+		{Elo0: 20, Elo1: 20, Alpha: 0.05, Beta: 0.05},
+		{Elo0: 20, Elo1: 0, Alpha: 0.05, Beta: 0.05},
+		{Elo0: 0, Elo1: 20, Alpha: 0, Beta: 0.05},
+	} {
+		if _, err := sim.RunSPRT(config); err == nil {
+			t.Errorf("Expected an error for %+v", config)
+		}
+	}
+}
+
+func TestCombinations(t *testing.T) {
+	combos := combinations(5, 3)
+	if len(combos) != 10 {
+		t.Fatalf("Expected 10 combinations of 3 from 5, got %d", len(combos))
+	}
+	if !reflect.DeepEqual(combos[0], []int{0, 1, 2}) || !reflect.DeepEqual(combos[9], []int{2, 3, 4}) {
+		t.Errorf("Expected combinations in lexicographic order, got %v", combos)
+	}
+}
+
+func TestFinishedAhead(t *testing.T) {
+	outcome := gameOutcome{winners: []int{0}, scores: []int{210, 150, 150, 120}}
+	won := []bool{true, false, false, false}
+
+	if got := finishedAhead(outcome, won, 0, 1); got != 1 {
+		t.Errorf("Expected the winner to finish ahead, got %.1f", got)
+	}
+	if got := finishedAhead(outcome, won, 3, 0); got != 0 {
+		t.Errorf("Expected a loser to finish behind the winner, got %.1f", got)
+	}
+	if got := finishedAhead(outcome, won, 1, 2); got != 0.5 {
+		t.Errorf("Expected equal scores to count half, got %.1f", got)
+	}
+	if got := finishedAhead(outcome, won, 1, 3); got != 1 {
+		t.Errorf("Expected the higher score to finish ahead, got %.1f", got)
+	}
+}
+
+func TestTournamentSeatsEveryCombination(t *testing.T) {
+	sim := NewSimulator([]AlgorithmFactory{
+		func() game.Algorithm { return game.FromLegacy(algorithms.NewStopAtScoreAlgorithm(25)) },
+		func() game.Algorithm { return game.FromLegacy(algorithms.NewConservativeAlgorithm()) },
+		func() game.Algorithm { return game.FromLegacy(algorithms.NewAdaptiveAlgorithm()) },
+		func() game.Algorithm { return game.FromLegacy(algorithms.NewAlwaysHitAlgorithm()) },
+	}, Config{
+		NumGames: 20,
+		Deck:     game.OfficialDeck(),
+		SeatMode: "cycle",
+		Seed:     8,
+	})
+
+	if _, err := sim.tournament(5); err == nil {
+		t.Error("Expected an error for a table larger than the field")
+	}
+
+	results, err := sim.tournament(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, result := range results {
+		// Each algorithm sits at the 3 tables that include it, and meets each rival at 2 of them
+		if result.Games != 60 {
+			t.Errorf("%s: expected 60 games, got %d", result.AlgorithmName, result.Games)
+		}
+		for j, meetings := range result.Meetings {
+			if j != result.Index && meetings != 40 {
+				t.Errorf("%s: expected 40 games against #%d, got %d", result.AlgorithmName, j, meetings)
+			}
+		}
+	}
+
+	// Head-to-head results are two sides of the same games
+	for i := range results {
+		for j := range results {
+			if i != j && math.Abs(results[i].HeadToHead[j]+results[j].HeadToHead[i]-1) > 1e-9 {
+				t.Errorf("Head-to-head of #%d and #%d should sum to 1", i, j)
+			}
+		}
+	}
+}
+
+func TestRatingsUpdate(t *testing.T) {
+	ratings := NewRatings()
+	ratings.Update([]string{"A", "B", "C"}, []int{0, 1, 1})
+
+	a, b, c := ratings.Get("A"), ratings.Get("B"), ratings.Get("C")
+	if a.Rating <= DefaultInitialRating || b.Rating >= DefaultInitialRating {
+		t.Errorf("Expected the winner to gain and the others to lose, got %.1f, %.1f, %.1f", a.Rating, b.Rating, c.Rating)
+	}
+	if b.Rating != c.Rating {
+		t.Errorf("Expected players who finished level to move equally, got %.1f and %.1f", b.Rating, c.Rating)
+	}
+	if total := a.Rating + b.Rating + c.Rating; math.Abs(total-3*DefaultInitialRating) > 1e-9 {
+		t.Errorf("Expected rating points to be conserved, total %.3f", total)
+	}
+	if a.Games != 1 || b.Games != 1 {
+		t.Errorf("Expected one game each, got %d and %d", a.Games, b.Games)
+	}
+}
+
+func TestRatingsFreeze(t *testing.T) {
+	ratings := NewRatings()
+	ratings.Update([]string{"A", "B"}, []int{0, 1})
+	ratings.Freeze()
+
+	before := ratings.Get("A").Rating
+	ratings.Update([]string{"A", "New"}, []int{1, 0})
+
+	if ratings.Get("A").Rating != before {
+		t.Errorf("Expected a frozen rating to stay at %.1f, got %.1f", before, ratings.Get("A").Rating)
+	}
+	if ratings.Get("New").Rating <= DefaultInitialRating {
+		t.Errorf("Expected the new player to gain by winning, got %.1f", ratings.Get("New").Rating)
+	}
+}
+
+func TestRatingsSaveAndLoad(t *testing.T) {
+	path := t.TempDir() + "/ratings.json"
+
+	missing, err := LoadRatings(path)
+	if err != nil || len(missing.Players) != 0 {
+		t.Fatalf("Expected an empty ladder for a missing file, got %v, %v", missing, err)
+	}
+
+	ratings := NewRatings()
+	ratings.Update([]string{"A", "B"}, []int{0, 1})
+	if err := ratings.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := LoadRatings(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !reflect.DeepEqual(loaded.Players, ratings.Players) || loaded.K != ratings.K {
+		t.Errorf("Expected %v after a round trip, got %v", ratings.Players, loaded.Players)
+	}
+}
+
+func TestFinishingRanks(t *testing.T) {
+	outcome := gameOutcome{winners: []int{2}, scores: []int{150, 180, 205, 150}}
+
+	ranks := finishingRanks(outcome)
+	if !reflect.DeepEqual(ranks, []int{2, 1, 0, 2}) {
+		t.Errorf("Expected ranks [2 1 0 2], got %v", ranks)
+	}
+}
+
+func TestDuplicateRotatesEveryDealThroughEverySeat(t *testing.T) {
+	sim := NewSimulator([]AlgorithmFactory{
+		func() game.Algorithm { return game.FromLegacy(algorithms.NewStopAtScoreAlgorithm(25)) },
+		func() game.Algorithm { return game.FromLegacy(algorithms.NewConservativeAlgorithm()) },
+		func() game.Algorithm { return game.FromLegacy(algorithms.NewAdaptiveAlgorithm()) },
+	}, Config{
+		NumGames:  10,
+		Deck:      game.OfficialDeck(),
+		SeatMode:  "random",
+		Seed:      21,
+		Duplicate: true,
+	})
+
+	if sim.NumGames() != 12 {
+		t.Fatalf("Expected 10 games to round up to 4 whole deals of 3, got %d", sim.NumGames())
+	}
+
+	for deal := 0; deal < 4; deal++ {
+		seats := make([]map[int]bool, 3)
+		for i := range seats {
+			seats[i] = make(map[int]bool)
+		}
+
+		var seed int64
+		for rotation := 0; rotation < 3; rotation++ {
+			outcome := sim.playGame(deal*3+rotation, sim.algorithms, nil)
+			if rotation == 0 {
+				seed = outcome.seed
+			} else if outcome.seed != seed {
+				t.Fatalf("Deal %d: every rotation should replay the same seed", deal)
+			}
+			for i, playerID := range outcome.players {
+				seats[i][playerID] = true
+			}
+		}
+
+		for i := range seats {
+			if len(seats[i]) != 3 {
+				t.Errorf("Deal %d: algorithm %d should sit in every seat, got %v", deal, i, seats[i])
+			}
+		}
+	}
+}
+
+func TestRunReturnsResults(t *testing.T) {
+	sim := newTestSimulator(13, 2)
+	sim.out = io.Discard
+
+	results := sim.Run()
+	if results.Config.Seed != 13 || results.Config.NumGames != 200 || results.Config.Deck.Name != "official" {
+		t.Errorf("Expected the run configuration in the results, got %+v", results.Config)
+	}
+	if len(results.Algorithms) != 3 {
+		t.Fatalf("Expected results for 3 algorithms, got %d", len(results.Algorithms))
+	}
+
+	totalWins := 0
+	for _, result := range results.Algorithms {
+		totalWins += result.GamesWon
+		if len(result.SeatGames) != 3 || len(result.PValues) != 3 {
+			t.Errorf("%s: expected a value per seat and per algorithm", result.AlgorithmName)
+		}
+	}
+	if totalWins < 200 {
+		t.Errorf("Expected at least one winner per game, got %d wins", totalWins)
+	}
+}
+
+func TestWriteJSONAndCSV(t *testing.T) {
+	sim := newTestSimulator(13, 2)
+	sim.out = io.Discard
+	results := sim.Run()
+
+	var jsonOut bytes.Buffer
+	if err := WriteJSON(&jsonOut, results); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+	var decoded RunResults
+	if err := json.Unmarshal(jsonOut.Bytes(), &decoded); err != nil {
+		t.Fatalf("WriteJSON wrote invalid JSON: %v", err)
+	}
+	if decoded.Config.Seed != 13 || decoded.Algorithms[0].GamesWon != results.Algorithms[0].GamesWon {
+		t.Error("Expected the JSON to round trip the results")
+	}
+
+	var csvOut bytes.Buffer
+	if err := WriteCSV(&csvOut, results); err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+	rows, err := csv.NewReader(&csvOut).ReadAll()
+	if err != nil {
+		t.Fatalf("WriteCSV wrote invalid CSV: %v", err)
+	}
+	if len(rows) != 4 || len(rows[0]) != 12+2*3 || rows[1][0] != results.Algorithms[0].AlgorithmName {
+		t.Errorf("Expected a header and a row per algorithm with seat columns, got %v", rows)
+	}
+}
+
+func TestEventLogIndependentOfWorkerCount(t *testing.T) {
+	logRun := func(workers int, sample float64) string {
+		var out bytes.Buffer
+		sim := newTestSimulator(17, workers)
+		sim.out = io.Discard
+		sim.numGames = 50
+		sim.eventLog = NewEventLog(&out)
+		sim.eventSampleRate = sample
+		sim.Run()
+		if err := sim.eventLog.Err(); err != nil {
+			t.Fatalf("Writing the event log failed: %v", err)
+		}
+		return out.String()
+	}
+
+	full := logRun(1, 1)
+	if full != logRun(4, 1) {
+		t.Error("Expected the same event log regardless of worker count")
+	}
+
+	games := make(map[int]bool)
+	for _, line := range strings.Split(strings.TrimSpace(full), "\n") {
+		var event game.Event
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("Invalid event line %q: %v", line, err)
+		}
+		games[event.Game] = true
+	}
+	if len(games) != 50 {
+		t.Errorf("Expected events for all 50 games, got %d", len(games))
+	}
+
+	sampled := logRun(4, 0.2)
+	if len(sampled) == 0 || len(sampled) >= len(full) {
+		t.Errorf("Expected sampling to log some but not all games, got %d of %d bytes", len(sampled), len(full))
+	}
+}
+
+func TestReplayEventsReproducesLoggedGame(t *testing.T) {
+	var log bytes.Buffer
+	sim := newTestSimulator(23, 2)
+	sim.out = io.Discard
+	sim.numGames = 10
+	sim.eventLog = NewEventLog(&log)
+	sim.Run()
+
+	events, err := ReadGameEvents(bytes.NewReader(log.Bytes()), 6)
+	if err != nil {
+		t.Fatalf("ReadGameEvents failed: %v", err)
+	}
+	if events[0].Game != 6 || events[len(events)-1].Type != "game_over" {
+		t.Fatalf("Expected game 6 from game_start to game_over, got game %d ending in %q", events[0].Game, events[len(events)-1].Type)
+	}
+
+	replayed, err := ReplayEvents(events[0], sim.newAlgorithms())
+	if err != nil {
+		t.Fatalf("ReplayEvents failed: %v", err)
+	}
+
+	// Compare as logged, after a trip through JSON
+	for i := range events {
+		if i >= len(replayed) {
+			t.Fatalf("Replay stopped after %d of %d events", len(replayed), len(events))
+		}
+		logged, _ := json.Marshal(events[i])
+		played, _ := json.Marshal(replayed[i])
+		if string(logged) != string(played) {
+			t.Fatalf("Event %d differs:\n%s\n%s", i, logged, played)
+		}
+	}
+	if len(replayed) != len(events) {
+		t.Errorf("Expected %d replayed events, got %d", len(events), len(replayed))
+	}
+
+	if _, err := ReadGameEvents(bytes.NewReader(log.Bytes()), 99); err == nil {
+		t.Error("Expected an error for a game missing from the log")
+	}
+}
+
+func newTestEvolveConfig() EvolveConfig {
+	return EvolveConfig{
+		Weights:     len(algorithms.GenomeFeatures),
+		Population:  6,
+		Generations: 3,
+		Games:       20,
+		Seed:        5,
+		Opponents:   []string{"Stop at 25"},
+		Deck:        game.OfficialDeck(),
+		Workers:     2,
+		NewAlgorithm: func(weights []float64) game.Algorithm {
+			return algorithms.NewWeightedAlgorithm(&algorithms.Genome{Name: "Evolved", Weights: weights})
+		},
+		NewOpponents: []AlgorithmFactory{
+			func() game.Algorithm { return game.FromLegacy(algorithms.NewStopAtScoreAlgorithm(25)) },
+		},
+	}
+}
+
+func TestEvolveResumesFromCheckpoint(t *testing.T) {
+	generations := 0
+	full, err := Evolve(newTestEvolveConfig(), nil, func(*EvolveCheckpoint) error {
+		generations++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Evolve failed: %v", err)
+	}
+	if generations != 3 || full.Generation != 3 || len(full.History) != 3 {
+		t.Fatalf("Expected 3 generations, got %d reported and %d in the checkpoint", generations, full.Generation)
+	}
+	for i := 1; i < len(full.Population); i++ {
+		if full.Population[i].Fitness > full.Population[i-1].Fitness {
+			t.Errorf("Expected the population ranked fittest first, got %+v", full.Population)
+		}
+	}
+
+	// Stop after the second generation and carry on from its checkpoint on disk
+	path := t.TempDir() + "/checkpoint.json"
+	config := newTestEvolveConfig()
+	config.Generations = 2
+	partial, err := Evolve(config, nil, nil)
+	if err != nil {
+		t.Fatalf("Evolve failed: %v", err)
+	}
+	if err := partial.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := LoadEvolveCheckpoint(path)
+	if err != nil {
+		t.Fatalf("LoadEvolveCheckpoint failed: %v", err)
+	}
+	config = loaded.Config
+	config.Generations = 3
+	config.NewAlgorithm = newTestEvolveConfig().NewAlgorithm
+	config.NewOpponents = newTestEvolveConfig().NewOpponents
+	resumed, err := Evolve(config, loaded, nil)
+	if err != nil {
+		t.Fatalf("Evolve failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(resumed.Population, full.Population) || !reflect.DeepEqual(resumed.History, full.History) {
+		t.Errorf("Expected a resumed run to match an uninterrupted one, got %+v and %+v", resumed.History, full.History)
+	}
+}
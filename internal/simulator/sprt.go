@@ -0,0 +1,165 @@
+package simulator
+
+import (
+	"fmt"
+	"math"
+)
+
+// DefaultSPRTMaxGames caps a head-to-head test that is not given a game limit
+const DefaultSPRTMaxGames = 100000
+
+// SPRTConfig holds the settings for a sequential head-to-head test
+type SPRTConfig struct {
+	Elo0        float64 // H0: the candidate is this many Elo stronger than the baseline
+	Elo1        float64 // H1: the candidate is this many Elo stronger than the baseline; must exceed Elo0
+	Alpha       float64 // Chance of accepting H1 when H0 is true
+	Beta        float64 // Chance of accepting H0 when H1 is true
+	MaxGames    int     // Stop without a verdict after this many games; 0 = DefaultSPRTMaxGames
+	ReportEvery int     // Print the log-likelihood ratio every this many games
+}
+
+// SPRTResult holds the outcome of a sequential head-to-head test
+type SPRTResult struct {
+	Candidate  string
+	Baseline   string
+	Games      int
+	Wins       int // Games the candidate won outright
+	Draws      int // Games the candidate and baseline shared
+	Losses     int // Games the baseline won outright
+	LLR        float64
+	LowerBound float64
+	UpperBound float64
+	Verdict    string // "H1" (candidate is stronger), "H0" (it is not) or "inconclusive"
+}
+
+// Score returns the candidate's mean score, counting a shared win as half
+func (r SPRTResult) Score() float64 {
+	if r.Games == 0 {
+		return 0.5
+	}
+	return (float64(r.Wins) + float64(r.Draws)/2) / float64(r.Games)
+}
+
+// add records one game from the candidate's point of view and updates the LLR
+func (r *SPRTResult) add(outcome gameOutcome, config SPRTConfig) {
+	won := make([]bool, 2)
+	for _, winner := range outcome.winners {
+		won[winner] = true
+	}
+
+	switch {
+	case won[0] && won[1]:
+		r.Draws++
+	case won[0]:
+		r.Wins++
+	default:
+		r.Losses++
+	}
+	r.Games++
+
+	r.LLR = LogLikelihoodRatio(r.Wins, r.Draws, r.Losses, config.Elo0, config.Elo1)
+	if r.LLR <= r.LowerBound {
+		r.Verdict = "H0"
+	} else if r.LLR >= r.UpperBound {
+		r.Verdict = "H1"
+	}
+}
+
+// RunSPRT plays the first algorithm (the candidate) against the second (the
+// baseline) until a sequential probability ratio test accepts H0 or H1 or the
+// game limit is reached, reporting the log-likelihood ratio as it goes. Games
+// are played in batches on the worker pool but counted in game order, so a
+// seeded test always stops at the same game.
+func (s *Simulator) RunSPRT(config SPRTConfig) (SPRTResult, error) {
+	if len(s.algorithms) != 2 {
+		return SPRTResult{}, fmt.Errorf("a head-to-head test needs exactly two algorithms, got %d", len(s.algorithms))
+	}
+	if config.Elo1 <= config.Elo0 {
+		return SPRTResult{}, fmt.Errorf("elo1 (%g) must be greater than elo0 (%g)", config.Elo1, config.Elo0)
+	}
+	if config.Alpha <= 0 || config.Alpha >= 1 || config.Beta <= 0 || config.Beta >= 1 {
+		return SPRTResult{}, fmt.Errorf("alpha and beta must be between 0 and 1")
+	}
+	if config.MaxGames <= 0 {
+		config.MaxGames = DefaultSPRTMaxGames
+	}
+
+	reportEvery := config.ReportEvery
+	if reportEvery <= 0 {
+		reportEvery = 100
+	}
+
+	result := SPRTResult{
+		Candidate: s.algorithms[0].GetName(),
+		Baseline:  s.algorithms[1].GetName(),
+	}
+	result.LowerBound, result.UpperBound = SPRTBounds(config.Alpha, config.Beta)
+
+	s.displaySPRTHeader(result, config)
+
+	for result.Verdict == "" {
+		batch := reportEvery
+		if result.Games+batch > config.MaxGames {
+			batch = config.MaxGames - result.Games
+		}
+		if batch <= 0 {
+			result.Verdict = "inconclusive"
+			break
+		}
+
+		// Every game of the batch is played, but counting stops at the game that decides the test
+		s.runGames(result.Games, batch, func(gameNum int, outcome gameOutcome) {
+			if result.Verdict != "" {
+				return
+			}
+			result.add(outcome, config)
+		})
+
+		s.displaySPRTProgress(result)
+	}
+
+	s.displaySPRTResult(result)
+
+	return result, nil
+}
+
+// displaySPRTHeader shows the hypotheses and settings of a head-to-head test
+func (s *Simulator) displaySPRTHeader(result SPRTResult, config SPRTConfig) {
+	fmt.Fprintf(s.out, "=== SPRT: %s vs %s ===\n", result.Candidate, result.Baseline)
+	fmt.Fprintf(s.out, "Seed: %d\n", s.seed)
+	fmt.Fprintf(s.out, "Game: %s\n", s.gameConfig)
+	fmt.Fprintf(s.out, "Deck: %s (%d cards)\n", s.deck.Name, s.deck.Size())
+	fmt.Fprintf(s.out, "Seating: %s\n", s.seatingDescription())
+	fmt.Fprintf(s.out, "Tie-breaker: %s\n", s.tieBreaker.GetName())
+	fmt.Fprintf(s.out, "H0: elo %+.1f, H1: elo %+.1f, alpha %.3f, beta %.3f\n", config.Elo0, config.Elo1, config.Alpha, config.Beta)
+	fmt.Fprintf(s.out, "LLR bounds: [%.2f, %.2f]\n\n", result.LowerBound, result.UpperBound)
+}
+
+// displaySPRTProgress shows the running totals and log-likelihood ratio
+func (s *Simulator) displaySPRTProgress(result SPRTResult) {
+	fmt.Fprintf(s.out, "Games %6d  W-D-L %d-%d-%d  score %.3f  LLR %6.2f [%.2f, %.2f]\n",
+		result.Games, result.Wins, result.Draws, result.Losses,
+		result.Score(), result.LLR, result.LowerBound, result.UpperBound)
+}
+
+// displaySPRTResult shows the verdict of a head-to-head test
+func (s *Simulator) displaySPRTResult(result SPRTResult) {
+	fmt.Fprintf(s.out, "\n=== SPRT Result ===\n")
+
+	elo := EloDifference(result.Score())
+	if math.IsInf(elo, 0) {
+		fmt.Fprintf(s.out, "Elo difference: %+.0f\n", elo)
+	} else {
+		fmt.Fprintf(s.out, "Elo difference: %+.1f\n", elo)
+	}
+
+	switch result.Verdict {
+	case "H1":
+		fmt.Fprintf(s.out, "H1 accepted after %d games: %s is stronger than %s\n", result.Games, result.Candidate, result.Baseline)
+	case "H0":
+		fmt.Fprintf(s.out, "H0 accepted after %d games: %s is not stronger than %s\n", result.Games, result.Candidate, result.Baseline)
+	default:
+		fmt.Fprintf(s.out, "Inconclusive after %d games (LLR %.2f)\n", result.Games, result.LLR)
+	}
+	fmt.Fprintf(s.out, "\n")
+}
@@ -0,0 +1,100 @@
+package simulator
+
+import "math"
+
+// z95 is the two-sided 95% critical value of the standard normal distribution
+const z95 = 1.959963984540054
+
+// significanceLevel is the p-value below which two algorithms are told apart
+const significanceLevel = 0.05
+
+// WilsonInterval returns the Wilson score confidence interval for a proportion
+// of successes out of trials, for the given critical value z
+func WilsonInterval(successes, trials int, z float64) (float64, float64) {
+	if trials == 0 {
+		return 0, 1
+	}
+
+	n := float64(trials)
+	p := float64(successes) / n
+	denominator := 1 + z*z/n
+	center := (p + z*z/(2*n)) / denominator
+	margin := z * math.Sqrt(p*(1-p)/n+z*z/(4*n*n)) / denominator
+
+	return math.Max(0, center-margin), math.Min(1, center+margin)
+}
+
+// StandardError returns the standard error of the mean of n values with the given sum and sum of squares
+func StandardError(sum, sumSquares float64, n int) float64 {
+	if n < 2 {
+		return 0
+	}
+
+	mean := sum / float64(n)
+	variance := (sumSquares - float64(n)*mean*mean) / float64(n-1)
+	if variance < 0 {
+		variance = 0
+	}
+	return math.Sqrt(variance / float64(n))
+}
+
+// SignTestPValue returns the two-sided p-value that two algorithms are equally
+// strong, given how many games each won that the other did not. Games both
+// won or both lost carry no information. It uses the normal approximation to
+// the binomial with a continuity correction.
+func SignTestPValue(winsA, winsB int) float64 {
+	decisive := winsA + winsB
+	if decisive == 0 {
+		return 1
+	}
+
+	diff := math.Abs(float64(winsA-winsB)) - 1
+	if diff < 0 {
+		diff = 0
+	}
+	z := diff / math.Sqrt(float64(decisive))
+
+	return math.Erfc(z / math.Sqrt2)
+}
+
+// ExpectedScore returns the expected score (win = 1, shared win = 0.5) of a
+// player rated elo points above their opponent
+func ExpectedScore(elo float64) float64 {
+	return 1 / (1 + math.Pow(10, -elo/400))
+}
+
+// EloDifference returns the Elo difference implied by an expected score
+func EloDifference(score float64) float64 {
+	if score <= 0 {
+		return math.Inf(-1)
+	}
+	if score >= 1 {
+		return math.Inf(1)
+	}
+	return -400 * math.Log10(1/score-1)
+}
+
+// SPRTBounds returns the log-likelihood ratio bounds at which a sequential
+// probability ratio test accepts H0 (lower) or H1 (upper)
+func SPRTBounds(alpha, beta float64) (float64, float64) {
+	return math.Log(beta / (1 - alpha)), math.Log((1 - beta) / alpha)
+}
+
+// LogLikelihoodRatio returns the generalized SPRT log-likelihood ratio of H1
+// (the candidate is elo1 stronger) against H0 (it is elo0 stronger), using the
+// normal approximation of the candidate's mean score over its wins, shared wins
+// and losses. Half a win and half a loss are added to the results, so a run of
+// identical results still has a variance and a lopsided match reaches a verdict.
+func LogLikelihoodRatio(wins, draws, losses int, elo0, elo1 float64) float64 {
+	if wins+draws+losses == 0 {
+		return 0
+	}
+
+	w, d, l := float64(wins)+0.5, float64(draws), float64(losses)+0.5
+	n := w + d + l
+	score := (w + d/2) / n
+	variance := (w*(1-score)*(1-score) + d*(0.5-score)*(0.5-score) + l*score*score) / n
+
+	s0, s1 := ExpectedScore(elo0), ExpectedScore(elo1)
+	return n / 2 * ((score-s0)*(score-s0) - (score-s1)*(score-s1)) / variance
+}
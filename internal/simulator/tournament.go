@@ -0,0 +1,238 @@
+package simulator
+
+import (
+	"flip7-simulator/internal/game"
+	"fmt"
+)
+
+// TournamentResult holds an algorithm's results across every table of a tournament
+type TournamentResult struct {
+	AlgorithmName string
+	Index         int // Position of the algorithm in the field
+	Games         int
+	GamesWon      int
+	TotalScore    int
+	AverageScore  float64
+	Meetings      []int     // Games played at the same table as each other algorithm (by Index)
+	HeadToHead    []float64 // Share of those games finished ahead of each other algorithm, ties counting half
+	headToHead    []float64 // Points behind HeadToHead: 1 per game finished ahead, 0.5 per tie
+}
+
+// HeadToHeadScore returns the share of games finished ahead of an opponent, across all opponents met
+func (r TournamentResult) HeadToHeadScore() float64 {
+	points, meetings := 0.0, 0
+	for j := range r.Meetings {
+		points += r.headToHead[j]
+		meetings += r.Meetings[j]
+	}
+	if meetings == 0 {
+		return 0
+	}
+	return points / float64(meetings)
+}
+
+// RunTournament plays every combination of tableSize algorithms from the field at
+// its own table, for the run's number of games per table, and shows the standings
+// along with a cross-table of head-to-head results
+func (s *Simulator) RunTournament(tableSize int) ([]TournamentResult, error) {
+	results, err := s.tournament(tableSize)
+	if err != nil {
+		return nil, err
+	}
+	s.displayTournament(results, tableSize)
+	return results, nil
+}
+
+// tournament plays every table of a round-robin tournament and totals the results per algorithm
+func (s *Simulator) tournament(tableSize int) ([]TournamentResult, error) {
+	numAlgorithms := len(s.algorithms)
+	if tableSize < 2 || tableSize > numAlgorithms {
+		return nil, fmt.Errorf("table size must be between 2 and %d", numAlgorithms)
+	}
+
+	results := make([]TournamentResult, numAlgorithms)
+	for i, algo := range s.algorithms {
+		results[i] = TournamentResult{
+			AlgorithmName: algo.GetName(),
+			Index:         i,
+			Meetings:      make([]int, numAlgorithms),
+			HeadToHead:    make([]float64, numAlgorithms),
+			headToHead:    make([]float64, numAlgorithms),
+		}
+	}
+
+	tables := combinations(numAlgorithms, tableSize)
+	progressEvery := len(tables) / 10
+	if progressEvery < 1 {
+		progressEvery = 1
+	}
+
+	for t, seats := range tables {
+		table := s.table(seats, DeriveSeed(s.seed, t))
+
+		table.runGames(0, table.numGames, func(gameNum int, outcome gameOutcome) {
+			table.rateGame(outcome)
+
+			won := make([]bool, len(seats))
+			for _, winner := range outcome.winners {
+				won[winner] = true
+			}
+
+			for a, i := range seats {
+				results[i].Games++
+				results[i].TotalScore += outcome.scores[a]
+				if won[a] {
+					results[i].GamesWon++
+				}
+
+				for b, j := range seats {
+					if a == b {
+						continue
+					}
+					results[i].Meetings[j]++
+					results[i].headToHead[j] += finishedAhead(outcome, won, a, b)
+				}
+			}
+		})
+
+		if (t+1)%progressEvery == 0 {
+			fmt.Fprintf(s.out, "Completed %d/%d tables\n", t+1, len(tables))
+		}
+	}
+
+	for i := range results {
+		if results[i].Games > 0 {
+			results[i].AverageScore = float64(results[i].TotalScore) / float64(results[i].Games)
+		}
+		for j := range results[i].Meetings {
+			if results[i].Meetings[j] > 0 {
+				results[i].HeadToHead[j] = results[i].headToHead[j] / float64(results[i].Meetings[j])
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// table returns a simulator for a single tournament table, seating the given
+// algorithms of the field and seeding its games from the table seed
+func (s *Simulator) table(seats []int, seed int64) *Simulator {
+	table := *s
+	table.factories = make([]AlgorithmFactory, len(seats))
+	table.algorithms = make([]game.Algorithm, len(seats))
+	for a, i := range seats {
+		table.factories[a] = s.factories[i]
+		table.algorithms[a] = s.algorithms[i]
+	}
+	table.seed = seed
+	table.numGames = s.gamesPerTable(len(seats))
+	return &table
+}
+
+// gamesPerTable returns how many games each table plays, rounded up to whole deals in duplicate mode
+func (s *Simulator) gamesPerTable(tableSize int) int {
+	if s.duplicate {
+		return wholeDeals(s.numGames, tableSize)
+	}
+	return s.numGames
+}
+
+// finishedAhead scores player a against player b in one game: 1 if a finished
+// ahead, 0.5 if they finished level and 0 if b finished ahead. Winning beats
+// losing; otherwise the higher final score is ahead.
+func finishedAhead(outcome gameOutcome, won []bool, a, b int) float64 {
+	switch {
+	case won[a] != won[b]:
+		if won[a] {
+			return 1
+		}
+		return 0
+	case outcome.scores[a] > outcome.scores[b] && !won[a]:
+		return 1
+	case outcome.scores[a] < outcome.scores[b] && !won[a]:
+		return 0
+	default:
+		return 0.5
+	}
+}
+
+// combinations returns every way to choose k of the indices 0 to n-1, in lexicographic order
+func combinations(n, k int) [][]int {
+	var all [][]int
+	combo := make([]int, k)
+
+	var choose func(start, depth int)
+	choose = func(start, depth int) {
+		if depth == k {
+			all = append(all, append([]int(nil), combo...))
+			return
+		}
+		for i := start; i <= n-(k-depth); i++ {
+			combo[depth] = i
+			choose(i+1, depth+1)
+		}
+	}
+	choose(0, 0)
+
+	return all
+}
+
+// displayTournament shows the tournament standings and head-to-head cross-table
+func (s *Simulator) displayTournament(results []TournamentResult, tableSize int) {
+	numTables := len(combinations(len(results), tableSize))
+
+	fmt.Fprintf(s.out, "\n=== Flip 7 Tournament Results ===\n")
+	fmt.Fprintf(s.out, "Tables: %d of %d players, %d games each\n", numTables, tableSize, s.gamesPerTable(tableSize))
+	fmt.Fprintf(s.out, "Seed: %d\n", s.seed)
+	fmt.Fprintf(s.out, "Game: %s\n", s.gameConfig)
+	fmt.Fprintf(s.out, "Deck: %s (%d cards)\n", s.deck.Name, s.deck.Size())
+	fmt.Fprintf(s.out, "Seating: %s\n", s.seatingDescription())
+	fmt.Fprintf(s.out, "Tie-breaker: %s\n\n", s.tieBreaker.GetName())
+
+	// Sort by head-to-head score
+	for i := 0; i < len(results)-1; i++ {
+		for j := i + 1; j < len(results); j++ {
+			if results[j].HeadToHeadScore() > results[i].HeadToHeadScore() {
+				results[i], results[j] = results[j], results[i]
+			}
+		}
+	}
+
+	fmt.Fprintf(s.out, "%-20s %8s %8s %8s %12s %8s\n", "Algorithm", "Games", "Wins", "Win%", "Avg Score", "H2H%")
+	fmt.Fprintf(s.out, "%-20s %8s %8s %8s %12s %8s\n", "=========", "=====", "====", "====", "=========", "====")
+
+	for _, result := range results {
+		fmt.Fprintf(s.out, "%-20s %8d %8d %8s %12.1f %7.1f%%\n",
+			result.AlgorithmName,
+			result.Games,
+			result.GamesWon,
+			formatWinRate(result.GamesWon, result.Games),
+			result.AverageScore,
+			result.HeadToHeadScore()*100)
+	}
+
+	fmt.Fprintf(s.out, "\nA fair share of wins at a %d-player table is %.1f%%\n", tableSize, 100/float64(tableSize))
+
+	fmt.Fprintf(s.out, "\n=== Head-to-Head (%% of games at the same table the row finished ahead of the column) ===\n")
+	fmt.Fprintf(s.out, "%-20s", "Algorithm")
+	for j := range results {
+		fmt.Fprintf(s.out, " %7s", fmt.Sprintf("#%d", j+1))
+	}
+	fmt.Fprintf(s.out, "\n")
+
+	for i, a := range results {
+		fmt.Fprintf(s.out, "%-20s", fmt.Sprintf("#%d %s", i+1, a.AlgorithmName))
+		for j, b := range results {
+			if i == j || a.Meetings[b.Index] == 0 {
+				fmt.Fprintf(s.out, " %7s", "-")
+				continue
+			}
+			fmt.Fprintf(s.out, " %6.1f%%", a.HeadToHead[b.Index]*100)
+		}
+		fmt.Fprintf(s.out, "\n")
+	}
+
+	s.displayRatings()
+
+	fmt.Fprintf(s.out, "\n")
+}